@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Tuning constants for the live adaptive concurrency controller. Unlike
+// findOptimalConcurrency's one-shot probe-then-fix search (run once before
+// the timed benchmark), this controller keeps adjusting the worker pool's
+// effective concurrency throughout the main benchmark phase, tracking
+// whatever optimum the server's actual latency and error behavior implies
+// as load, caching, or contention shift over the run.
+const (
+	adaptiveAdjustInterval    = 3 * time.Second
+	adaptiveMinRequests       = 5
+	adaptiveTargetUtilization = 0.7
+	adaptiveErrorRateLimit    = 0.05
+	adaptiveIncreaseStep      = 1
+	defaultAdaptiveStart      = 4
+	adaptiveLimiterPoll       = 10 * time.Millisecond
+)
+
+// concurrencyLimiter bounds how many of a fixed pool of worker goroutines
+// may have a request in flight at once. Its limit can be changed live by
+// concurrencyController, so the effective concurrency moves up or down
+// without tearing down and respawning goroutines.
+type concurrencyLimiter struct {
+	limit  int64
+	active int64
+}
+
+func newConcurrencyLimiter(start int) *concurrencyLimiter {
+	return &concurrencyLimiter{limit: int64(start)}
+}
+
+// Acquire blocks until a slot is available under the current limit,
+// polling rather than blocking on a channel since the limit can change
+// while a goroutine is waiting. Returns false if ctx is done first.
+func (l *concurrencyLimiter) Acquire(ctx context.Context) bool {
+	for {
+		if atomic.AddInt64(&l.active, 1) <= atomic.LoadInt64(&l.limit) {
+			return true
+		}
+		atomic.AddInt64(&l.active, -1)
+
+		select {
+		case <-time.After(adaptiveLimiterPoll):
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+func (l *concurrencyLimiter) Release() {
+	atomic.AddInt64(&l.active, -1)
+}
+
+func (l *concurrencyLimiter) SetLimit(n int) {
+	atomic.StoreInt64(&l.limit, int64(n))
+}
+
+func (l *concurrencyLimiter) Limit() int {
+	return int(atomic.LoadInt64(&l.limit))
+}
+
+// runAdaptive runs the main benchmark phase with a live-adjusted
+// concurrency: a fixed pool of maxConcurrency worker goroutines gated by a
+// concurrencyLimiter, plus a controller goroutine that raises or lowers the
+// limiter's limit every adaptiveAdjustInterval. limiter is created by the
+// caller (runMainBenchmark) so the progress display can also poll its
+// live limit.
+func (b *Benchmarker) runAdaptive(ctx context.Context, model string, limiter *concurrencyLimiter, wg *sync.WaitGroup) {
+	maxConcurrency := b.config.MaxConcurrency
+	if maxConcurrency <= 0 {
+		maxConcurrency = maxAutoConcurrency
+	}
+	if limiter.Limit() > maxConcurrency {
+		maxConcurrency = limiter.Limit()
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		b.runConcurrencyController(ctx, limiter, maxConcurrency)
+	}()
+
+	for i := 0; i < maxConcurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b.adaptiveWorker(ctx, model, limiter)
+		}()
+	}
+}
+
+// runConcurrencyController adjusts limiter's limit on a timer using an
+// AIMD-style policy driven by the p95 latency and error rate observed in
+// just the most recent adaptiveAdjustInterval window - not the run's
+// cumulative stats, which would dilute a late-stage latency spike or error
+// burst under tens of thousands of earlier, healthy samples: additive
+// increase while comfortably under the p95 SLO, additive decrease once
+// over it, and an immediate multiplicative cut the moment the error rate
+// signals the server itself is struggling rather than just slow.
+func (b *Benchmarker) runConcurrencyController(ctx context.Context, limiter *concurrencyLimiter, maxConcurrency int) {
+	ticker := time.NewTicker(adaptiveAdjustInterval)
+	defer ticker.Stop()
+
+	prevTotal, prevSuccessful := b.metrics.RequestCounters()
+	prevLatency := b.metrics.LatencySnapshot()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			total, successful := b.metrics.RequestCounters()
+			latency := prevLatency
+
+			deltaTotal := total - prevTotal
+			deltaSuccessful := successful - prevSuccessful
+			prevTotal, prevSuccessful = total, successful
+			prevLatency = b.metrics.LatencySnapshot()
+
+			if deltaTotal < adaptiveMinRequests {
+				continue
+			}
+
+			windowErrorRate := float64(deltaTotal-deltaSuccessful) / float64(deltaTotal)
+			windowP95 := b.metrics.LatencyPercentileSince(latency, 95)
+
+			current := limiter.Limit()
+			next := current
+
+			switch {
+			case windowErrorRate > adaptiveErrorRateLimit:
+				next = current / 2
+			case windowP95 > b.config.SLOP95:
+				next = current - adaptiveIncreaseStep
+			case windowP95 < time.Duration(float64(b.config.SLOP95)*adaptiveTargetUtilization):
+				next = current + adaptiveIncreaseStep
+			}
+
+			if next < 1 {
+				next = 1
+			}
+			if next > maxConcurrency {
+				next = maxConcurrency
+			}
+			if next != current {
+				limiter.SetLimit(next)
+			}
+		}
+	}
+}
+
+// adaptiveWorker is runAdaptive's per-goroutine loop: it acquires a slot
+// from limiter before every request and releases it immediately after, so
+// the number of requests actually in flight tracks limiter.Limit().
+func (b *Benchmarker) adaptiveWorker(ctx context.Context, model string, limiter *concurrencyLimiter) {
+	for {
+		if !limiter.Acquire(ctx) {
+			return
+		}
+
+		prompt, maxOutputTokens := b.promptSource.Next()
+
+		var resp *CompletionResponse
+		var err error
+
+		if b.exporter != nil {
+			b.exporter.IncInFlight()
+		}
+
+		if b.config.Streaming {
+			resp, err = b.client.CreateStreamingCompletion(ctx, model, prompt, maxOutputTokens)
+		} else {
+			resp, err = b.client.CreateCompletion(ctx, model, prompt, maxOutputTokens)
+		}
+
+		b.metrics.RecordRequest(resp, err)
+		if b.exporter != nil {
+			b.exporter.DecInFlight()
+			b.exporter.RecordRequest(resp, err)
+		}
+
+		limiter.Release()
+	}
+}
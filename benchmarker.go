@@ -10,38 +10,48 @@ import (
 
 // Benchmarker orchestrates the entire benchmarking process
 type Benchmarker struct {
-	config  Config
-	client  *Client
-	metrics *Metrics
-	display *Display
+	config       Config
+	client       *Client
+	metrics      *Metrics
+	display      *Display
+	exporter     *MetricsExporter
+	promptSource PromptSource
 }
 
 // BenchmarkResult holds the final benchmark results
 type BenchmarkResult struct {
-	Model             string        `json:"model"`
-	Duration          time.Duration `json:"duration"`
-	TotalRequests     int64         `json:"total_requests"`
-	SuccessfulReqs    int64         `json:"successful_requests"`
-	FailedReqs        int64         `json:"failed_requests"`
-	RequestsPerSec    float64       `json:"requests_per_second"`
-	TokensPerSec      float64       `json:"tokens_per_second"`
-	InputTokensPerSec float64       `json:"input_tokens_per_second"`
-	OutputTokensPerSec float64      `json:"output_tokens_per_second"`
-	AvgLatency        time.Duration `json:"avg_latency"`
-	MinLatency        time.Duration `json:"min_latency"`
-	MaxLatency        time.Duration `json:"max_latency"`
-	P95Latency        time.Duration `json:"p95_latency"`
-	P99Latency        time.Duration `json:"p99_latency"`
-	AvgTTFT           time.Duration `json:"avg_ttft"`
-	ErrorRate         float64       `json:"error_rate"`
-	Errors            map[string]int `json:"errors"`
+	Model              string            `json:"model"`
+	Duration           time.Duration     `json:"duration"`
+	TotalRequests      int64             `json:"total_requests"`
+	SuccessfulReqs     int64             `json:"successful_requests"`
+	FailedReqs         int64             `json:"failed_requests"`
+	RequestsPerSec     float64           `json:"requests_per_second"`
+	TokensPerSec       float64           `json:"tokens_per_second"`
+	InputTokensPerSec  float64           `json:"input_tokens_per_second"`
+	OutputTokensPerSec float64           `json:"output_tokens_per_second"`
+	AvgLatency         time.Duration     `json:"avg_latency"`
+	MinLatency         time.Duration     `json:"min_latency"`
+	MaxLatency         time.Duration     `json:"max_latency"`
+	StdDevLatency      time.Duration     `json:"stddev_latency"`
+	P50Latency         time.Duration     `json:"p50_latency"`
+	P75Latency         time.Duration     `json:"p75_latency"`
+	P90Latency         time.Duration     `json:"p90_latency"`
+	P95Latency         time.Duration     `json:"p95_latency"`
+	P99Latency         time.Duration     `json:"p99_latency"`
+	P999Latency        time.Duration     `json:"p999_latency"`
+	P9999Latency       time.Duration     `json:"p9999_latency"`
+	AvgTTFT            time.Duration     `json:"avg_ttft"`
+	ErrorRate          float64           `json:"error_rate"`
+	Errors             map[string]int    `json:"errors"`
+	LatencyBuckets     []HistogramBucket `json:"latency_buckets,omitempty"`
+	TTFTBuckets        []HistogramBucket `json:"ttft_buckets,omitempty"`
 }
 
 // NewBenchmarker creates a new benchmarker instance
 func NewBenchmarker(config Config) *Benchmarker {
 	return &Benchmarker{
 		config:  config,
-		client:  NewClient(config.ServerURL, config.Timeout, config.APIKey),
+		client:  NewClient(config.ServerURL, config.Timeout, config.APIKey, config.StreamIdleTimeout),
 		metrics: NewMetrics(),
 		display: NewDisplay(config.NoColor),
 	}
@@ -49,8 +59,21 @@ func NewBenchmarker(config Config) *Benchmarker {
 
 // Run executes the complete benchmark
 func (b *Benchmarker) Run(ctx context.Context) error {
+	if b.config.MetricsAddr != "" {
+		b.exporter = NewMetricsExporter(b.config.MetricsAddr)
+		if err := b.exporter.Start(); err != nil {
+			return fmt.Errorf("failed to start metrics exporter: %w", err)
+		}
+		b.display.PrintStatus(fmt.Sprintf("Serving Prometheus metrics on %s/metrics", b.config.MetricsAddr))
+		defer func() {
+			stopCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			b.exporter.Stop(stopCtx)
+		}()
+	}
+
 	var modelsToTest []string
-	
+
 	// If models are specified, use them directly (no discovery needed)
 	if len(b.config.Models) > 0 {
 		modelsToTest = b.config.Models
@@ -62,51 +85,57 @@ func (b *Benchmarker) Run(ctx context.Context) error {
 		if err := b.discoverModels(ctx); err != nil {
 			return fmt.Errorf("failed to discover models: %w", err)
 		}
-		
+
 		// Use all discovered models
 		modelsToTest = b.getModelsToTest()
 		if len(modelsToTest) == 0 {
 			return fmt.Errorf("no models available for testing")
 		}
 	}
-	
+
 	b.display.PrintHeader(b.config.ServerURL, modelsToTest)
-	
+
 	var results []BenchmarkResult
-	
+
 	// Benchmark each model
 	for _, model := range modelsToTest {
 		if ctx.Err() != nil {
 			break
 		}
-		
+
 		result, err := b.benchmarkModel(ctx, model)
 		if err != nil {
 			b.display.PrintError(fmt.Sprintf("Failed to benchmark model %s: %v", model, err))
 			continue
 		}
-		
+
 		results = append(results, result)
+
+		if b.config.PushgatewayURL != "" {
+			if err := PushBenchmarkResult(b.config.PushgatewayURL, b.config.PushgatewayJob, result); err != nil {
+				b.display.PrintError(fmt.Sprintf("Failed to push result for %s to Pushgateway: %v", model, err))
+			}
+		}
 	}
-	
+
 	// Display final results
 	b.display.PrintResults(results, b.config.Format)
-	
+
 	return nil
 }
 
 // discoverModels fetches available models from the server
 func (b *Benchmarker) discoverModels(ctx context.Context) error {
 	b.display.PrintStatus("Discovering available models...")
-	
+
 	models, err := b.client.ListModels(ctx)
 	if err != nil {
 		return err
 	}
-	
+
 	b.client.availableModels = models
 	b.display.PrintStatus(fmt.Sprintf("Found %d models", len(models)))
-	
+
 	return nil
 }
 
@@ -125,7 +154,7 @@ func (b *Benchmarker) getModelsToTest() []string {
 		}
 		return validModels
 	}
-	
+
 	// Use all available models
 	var models []string
 	for _, model := range b.client.availableModels {
@@ -137,13 +166,29 @@ func (b *Benchmarker) getModelsToTest() []string {
 // benchmarkModel runs the complete benchmark for a single model
 func (b *Benchmarker) benchmarkModel(ctx context.Context, model string) (BenchmarkResult, error) {
 	b.display.PrintModelHeader(model)
-	
+
 	// Reset metrics for this model
 	b.metrics.Reset()
-	
+	if b.exporter != nil {
+		b.exporter.Reset()
+		b.exporter.SetModel(model)
+	}
+
+	promptSource, err := b.buildPromptSource()
+	if err != nil {
+		return BenchmarkResult{}, err
+	}
+	b.promptSource = promptSource
+
 	// Determine optimal concurrency if not specified
 	concurrency := b.config.Concurrency
-	if concurrency == 0 {
+	if b.config.AdaptiveConcurrency {
+		// The live controller in runAdaptive takes it from here; it only
+		// needs a starting point, not a pre-run search.
+		if concurrency <= 0 {
+			concurrency = defaultAdaptiveStart
+		}
+	} else if concurrency == 0 {
 		var err error
 		concurrency, err = b.findOptimalConcurrency(ctx, model)
 		if err != nil {
@@ -156,39 +201,165 @@ func (b *Benchmarker) benchmarkModel(ctx context.Context, model string) (Benchma
 	} else if concurrency < 0 {
 		concurrency = 1
 	}
-	
+
 	// Warmup phase
 	if b.config.Warmup > 0 {
 		if err := b.warmup(ctx, model, concurrency); err != nil {
 			b.display.PrintError(fmt.Sprintf("Warmup failed: %v", err))
 		}
 	}
-	
+
 	// Main benchmark
 	result, err := b.runMainBenchmark(ctx, model, concurrency)
 	if err != nil {
 		return BenchmarkResult{}, err
 	}
-	
+
 	return result, nil
 }
 
-// findOptimalConcurrency determines the best concurrency level
+// concurrencyProbe is a cached result of testConcurrency at one level.
+type concurrencyProbe struct {
+	concurrency int
+	rps         float64
+	p95         time.Duration
+}
+
+const (
+	concurrencyProbeDuration = 10 * time.Second
+	concurrencyGainThreshold = 0.10
+	maxAutoConcurrency       = 256
+)
+
+// buildPromptSource constructs the PromptSource for this run, combining the
+// configured corpus (the built-in --prompt-size bucket, or a JSONL file of
+// ShareGPT-style conversations) with optional input-token-length targeting,
+// and makes the result safe for concurrent workers.
+func (b *Benchmarker) buildPromptSource() (PromptSource, error) {
+	var base PromptSource
+	if b.config.PromptFile != "" {
+		prompts, err := loadJSONLPrompts(b.config.PromptFile)
+		if err != nil {
+			return nil, err
+		}
+		base = newStaticPromptSource(prompts)
+	} else {
+		base = newStaticPromptSource(b.getPrompts())
+	}
+
+	switch {
+	case b.config.InputTokensDist != "":
+		dist, err := parseInputTokensDist(b.config.InputTokensDist)
+		if err != nil {
+			return nil, err
+		}
+		base = newTargetLengthPromptSource(base, approxTokenizer{}, dist, b.config.OutputTokens)
+	case b.config.OutputTokens > 0:
+		base = newFixedOutputPromptSource(base, b.config.OutputTokens)
+	}
+
+	return newSyncPromptSource(base), nil
+}
+
+// findOptimalConcurrency determines the best concurrency level. With
+// --auto-concurrency unset it keeps the historical behavior of a fixed
+// concurrency of 1, which is appropriate for cloud APIs with per-request
+// rate limits. With --auto-concurrency set, it climbs concurrency by
+// doubling while throughput keeps improving and p95 latency stays under
+// --slo-p95, then binary-searches the boundary for the largest concurrency
+// that still meets the SLO.
 func (b *Benchmarker) findOptimalConcurrency(ctx context.Context, model string) (int, error) {
-	b.display.PrintStatus("Using concurrency: 1 (default for cloud APIs)")
-	
-	// For cloud APIs, just use concurrency 1 without any testing
-	// This eliminates the extra failing request that was coming from the test
-	return 1, nil
+	if !b.config.AutoConcurrency {
+		b.display.PrintStatus("Using concurrency: 1 (default for cloud APIs)")
+		return 1, nil
+	}
+
+	b.display.PrintStatus(fmt.Sprintf("Auto-detecting concurrency (SLO p95: %v)...", b.config.SLOP95))
+
+	cache := make(map[int]concurrencyProbe)
+	probe := func(c int) (concurrencyProbe, error) {
+		if r, ok := cache[c]; ok {
+			return r, nil
+		}
+		rps, p95, err := b.testConcurrency(ctx, model, c, concurrencyProbeDuration)
+		if err != nil {
+			return concurrencyProbe{}, err
+		}
+		r := concurrencyProbe{concurrency: c, rps: rps, p95: p95}
+		cache[c] = r
+		b.display.PrintStatus(fmt.Sprintf("  probe c=%d: %.2f rps, p95=%v", c, rps, p95))
+		return r, nil
+	}
+
+	prev, err := probe(1)
+	if err != nil {
+		return 0, err
+	}
+
+	lastGood := prev
+	var failing concurrencyProbe
+	foundFailing := false
+
+	for c := 2; c <= maxAutoConcurrency; c *= 2 {
+		cur, probeErr := probe(c)
+		if probeErr != nil {
+			return 0, probeErr
+		}
+
+		if cur.p95 > b.config.SLOP95 {
+			failing = cur
+			foundFailing = true
+			break
+		}
+
+		gain := 0.0
+		if prev.rps > 0 {
+			gain = (cur.rps - prev.rps) / prev.rps
+		}
+		lastGood = cur
+		if gain < concurrencyGainThreshold {
+			b.display.PrintStatus(fmt.Sprintf("  throughput gain %.1f%% below %.0f%% threshold, stopping climb",
+				gain*100, concurrencyGainThreshold*100))
+			break
+		}
+
+		prev = cur
+	}
+
+	if !foundFailing {
+		b.display.PrintStatus(fmt.Sprintf("Auto-concurrency converged at %d (no SLO breach observed)", lastGood.concurrency))
+		return lastGood.concurrency, nil
+	}
+
+	// Binary search between the last good concurrency and the failing one
+	// for the largest concurrency that still meets the SLO.
+	lo, hi := lastGood.concurrency, failing.concurrency
+	for hi-lo > 1 {
+		mid := (lo + hi) / 2
+		cur, probeErr := probe(mid)
+		if probeErr != nil {
+			return 0, probeErr
+		}
+		if cur.p95 <= b.config.SLOP95 {
+			lo = mid
+			lastGood = cur
+		} else {
+			hi = mid
+		}
+	}
+
+	b.display.PrintStatus(fmt.Sprintf("Auto-concurrency converged at %d (p95=%v, SLO=%v)", lo, lastGood.p95, b.config.SLOP95))
+	return lo, nil
 }
 
-// testConcurrency runs a short test at a specific concurrency level
-func (b *Benchmarker) testConcurrency(ctx context.Context, model string, concurrency int, duration time.Duration) (float64, error) {
+// testConcurrency runs a short probe at a specific concurrency level and
+// reports its throughput and p95 latency.
+func (b *Benchmarker) testConcurrency(ctx context.Context, model string, concurrency int, duration time.Duration) (float64, time.Duration, error) {
 	tempMetrics := NewMetrics()
-	
+
 	ctx, cancel := context.WithTimeout(ctx, duration)
 	defer cancel()
-	
+
 	var wg sync.WaitGroup
 	for i := 0; i < concurrency; i++ {
 		wg.Add(1)
@@ -197,20 +368,20 @@ func (b *Benchmarker) testConcurrency(ctx context.Context, model string, concurr
 			b.worker(ctx, model, tempMetrics)
 		}()
 	}
-	
+
 	wg.Wait()
-	
+
 	stats := tempMetrics.GetStats()
-	return stats.RequestsPerSec, nil
+	return stats.RequestsPerSec, stats.P95Latency, nil
 }
 
 // warmup runs the warmup phase
 func (b *Benchmarker) warmup(ctx context.Context, model string, concurrency int) error {
 	b.display.PrintStatus(fmt.Sprintf("Warming up (%v)...", b.config.Warmup))
-	
+
 	ctx, cancel := context.WithTimeout(ctx, b.config.Warmup)
 	defer cancel()
-	
+
 	var wg sync.WaitGroup
 	for i := 0; i < concurrency; i++ {
 		wg.Add(1)
@@ -221,47 +392,85 @@ func (b *Benchmarker) warmup(ctx context.Context, model string, concurrency int)
 			b.worker(ctx, model, warmupMetrics)
 		}()
 	}
-	
+
 	wg.Wait()
 	return nil
 }
 
 // runMainBenchmark executes the main benchmark phase
 func (b *Benchmarker) runMainBenchmark(ctx context.Context, model string, concurrency int) (BenchmarkResult, error) {
-	b.display.PrintStatus(fmt.Sprintf("Running benchmark (concurrency: %d, duration: %v)...", 
-		concurrency, b.config.Duration))
-	
+	if b.config.AdaptiveConcurrency && strings.TrimSpace(b.config.Profile) == "" {
+		b.display.PrintStatus(fmt.Sprintf("Running benchmark (adaptive concurrency, starting at: %d, duration: %v)...",
+			concurrency, b.config.Duration))
+	} else {
+		b.display.PrintStatus(fmt.Sprintf("Running benchmark (concurrency: %d, duration: %v)...",
+			concurrency, b.config.Duration))
+	}
+
+	// For adaptive concurrency, the limiter is created here (rather than
+	// inside runAdaptive) so its live limit can be polled by the progress
+	// display as well as by the worker pool.
+	var liveLimiter *concurrencyLimiter
+	if b.config.AdaptiveConcurrency {
+		liveLimiter = newConcurrencyLimiter(concurrency)
+	}
+	concurrencyFn := func() int {
+		if liveLimiter != nil {
+			return liveLimiter.Limit()
+		}
+		return concurrency
+	}
+
 	// Start progress display in a separate goroutine
 	progressCtx, progressCancel := context.WithCancel(ctx)
 	progressDone := make(chan struct{})
-	
+
 	go func() {
-		b.display.ShowProgress(progressCtx, b.metrics, b.config.Duration)
+		b.display.ShowProgress(progressCtx, b.metrics, b.config.Duration, concurrencyFn)
 		close(progressDone)
 	}()
-	
+
 	// Run benchmark workers
 	benchCtx, benchCancel := context.WithTimeout(ctx, b.config.Duration)
 	defer benchCancel()
-	
+
+	profile, err := ParseLoadProfile(b.config.Profile, b.config.Duration)
+	if err != nil {
+		progressCancel()
+		<-progressDone
+		return BenchmarkResult{}, err
+	}
+
 	var wg sync.WaitGroup
-	for i := 0; i < concurrency; i++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			b.worker(benchCtx, model, b.metrics)
-		}()
+	if profile != nil {
+		b.runOpenLoop(benchCtx, model, concurrency, profile, &wg)
+	} else if b.config.AdaptiveConcurrency {
+		b.runAdaptive(benchCtx, model, liveLimiter, &wg)
+	} else {
+		for i := 0; i < concurrency; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				b.worker(benchCtx, model, b.metrics)
+			}()
+		}
 	}
-	
+
 	wg.Wait()
-	
+
 	// Cancel progress context and wait for it to finish
 	progressCancel()
 	<-progressDone
-	
+
 	// Get final stats
 	stats := b.metrics.GetStats()
-	
+
+	if b.config.LatencyHistogramOut != "" {
+		if err := dumpLatencyHistogram(b.config.LatencyHistogramOut, model, b.metrics.LatencyHistogram()); err != nil {
+			b.display.PrintError(fmt.Sprintf("Failed to write latency histogram for %s: %v", model, err))
+		}
+	}
+
 	return BenchmarkResult{
 		Model:              model,
 		Duration:           stats.Duration,
@@ -275,37 +484,111 @@ func (b *Benchmarker) runMainBenchmark(ctx context.Context, model string, concur
 		AvgLatency:         stats.AvgLatency,
 		MinLatency:         stats.MinLatency,
 		MaxLatency:         stats.MaxLatency,
+		StdDevLatency:      stats.StdDevLatency,
+		P50Latency:         stats.P50Latency,
+		P75Latency:         stats.P75Latency,
+		P90Latency:         stats.P90Latency,
 		P95Latency:         stats.P95Latency,
 		P99Latency:         stats.P99Latency,
-		AvgTTFT:           stats.AvgTTFT,
-		ErrorRate:         stats.ErrorRate,
-		Errors:            stats.Errors,
+		P999Latency:        stats.P999Latency,
+		P9999Latency:       stats.P9999Latency,
+		AvgTTFT:            stats.AvgTTFT,
+		ErrorRate:          stats.ErrorRate,
+		Errors:             stats.Errors,
+		LatencyBuckets:     b.metrics.LatencyHistogram().BucketCounts(),
+		TTFTBuckets:        b.metrics.TTFTHistogram().BucketCounts(),
 	}, nil
 }
 
-// worker runs continuous requests until context is cancelled
+// runOpenLoop runs the central scheduler for an open-loop LoadProfile and
+// hands each scheduled ticket to a pool of concurrency workers. Unlike the
+// closed-loop worker, request latency is measured from the scheduled-send
+// time, not from whenever a worker became free.
+func (b *Benchmarker) runOpenLoop(ctx context.Context, model string, concurrency int, profile LoadProfile, wg *sync.WaitGroup) {
+	tickets := profile.Run(ctx)
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case scheduledAt, ok := <-tickets:
+					if !ok {
+						return
+					}
+					b.fireOpenLoopRequest(ctx, model, scheduledAt)
+				}
+			}
+		}()
+	}
+}
+
+// fireOpenLoopRequest sends one request on behalf of the open-loop
+// scheduler and records it against scheduledAt.
+func (b *Benchmarker) fireOpenLoopRequest(ctx context.Context, model string, scheduledAt time.Time) {
+	prompt, maxOutputTokens := b.promptSource.Next()
+
+	var resp *CompletionResponse
+	var err error
+
+	if b.exporter != nil {
+		b.exporter.IncInFlight()
+	}
+
+	if b.config.Streaming {
+		resp, err = b.client.CreateStreamingCompletion(ctx, model, prompt, maxOutputTokens)
+	} else {
+		resp, err = b.client.CreateCompletion(ctx, model, prompt, maxOutputTokens)
+	}
+
+	if resp != nil {
+		resp.ScheduledAt = scheduledAt
+	}
+
+	b.metrics.RecordRequest(resp, err)
+	if b.exporter != nil {
+		b.exporter.DecInFlight()
+		b.exporter.RecordRequest(resp, err)
+	}
+}
+
+// worker runs continuous requests until context is cancelled. It is shared
+// by the main benchmark, the auto-concurrency probes (testConcurrency) and
+// warmup, each passing its own metrics instance (a throwaway one for the
+// probes and warmup); only requests
+// recorded against b.metrics - the main benchmark - are also folded into
+// the exporter, so probe/warmup traffic never blends into the "live"
+// counters a dashboard scrapes.
 func (b *Benchmarker) worker(ctx context.Context, model string, metrics *Metrics) {
-	prompts := b.getPrompts()
-	promptIndex := 0
-	
+	recordToExporter := b.exporter != nil && metrics == b.metrics
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		default:
-			prompt := prompts[promptIndex%len(prompts)]
-			promptIndex++
-			
+			prompt, maxOutputTokens := b.promptSource.Next()
+
 			var resp *CompletionResponse
 			var err error
-			
+
+			if recordToExporter {
+				b.exporter.IncInFlight()
+			}
+
 			if b.config.Streaming {
-				resp, err = b.client.CreateStreamingCompletion(ctx, model, prompt)
+				resp, err = b.client.CreateStreamingCompletion(ctx, model, prompt, maxOutputTokens)
 			} else {
-				resp, err = b.client.CreateCompletion(ctx, model, prompt)
+				resp, err = b.client.CreateCompletion(ctx, model, prompt, maxOutputTokens)
 			}
-			
+
 			metrics.RecordRequest(resp, err)
+			if recordToExporter {
+				b.exporter.DecInFlight()
+				b.exporter.RecordRequest(resp, err)
+			}
 		}
 	}
 }
@@ -313,7 +596,7 @@ func (b *Benchmarker) worker(ctx context.Context, model string, metrics *Metrics
 // getPrompts returns test prompts based on configured size
 func (b *Benchmarker) getPrompts() []string {
 	prompts := make(map[string][]string)
-	
+
 	prompts["small"] = []string{
 		"Hello, world!",
 		"What is 2+2?",
@@ -321,7 +604,7 @@ func (b *Benchmarker) getPrompts() []string {
 		"How are you?",
 		"What's the weather like?",
 	}
-	
+
 	prompts["medium"] = []string{
 		"Write a short story about a robot learning to paint.",
 		"Explain the concept of recursion in programming with an example.",
@@ -329,13 +612,13 @@ func (b *Benchmarker) getPrompts() []string {
 		"Describe the process of photosynthesis in plants.",
 		"How does machine learning differ from traditional programming approaches?",
 	}
-	
+
 	prompts["large"] = []string{
 		"You are a senior software engineer reviewing a pull request. The code implements a distributed cache system using Redis. Please provide a comprehensive code review covering architecture, performance, security, error handling, testing, and maintainability. Consider scalability concerns and suggest improvements for monitoring and observability. The system needs to handle 100,000 requests per second with sub-millisecond latency requirements.",
 		"Write a detailed technical specification for a real-time collaborative document editing system similar to Google Docs. Include the architecture design, data structures, conflict resolution algorithms, network protocols, security considerations, user authentication, permission management, and scalability strategies. Explain how you would handle concurrent edits, maintain consistency across multiple clients, and ensure data persistence.",
 		"Design a comprehensive monitoring and alerting system for a microservices architecture running on Kubernetes. The system should handle metrics collection, log aggregation, distributed tracing, anomaly detection, and automated incident response. Explain the technology stack, data flow, storage requirements, query optimization, dashboard design, and integration with existing DevOps tools.",
 	}
-	
+
 	switch b.config.PromptSize {
 	case "small":
 		return prompts["small"]
@@ -352,4 +635,4 @@ func (b *Benchmarker) getPrompts() []string {
 	default:
 		return prompts["medium"]
 	}
-}
\ No newline at end of file
+}
@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -12,15 +13,25 @@ import (
 	"net/url"
 	"os"
 	"strings"
+	"sync/atomic"
 	"time"
 )
 
+// ErrStreamStalled indicates a streaming completion stopped producing
+// tokens for longer than the configured stream idle timeout, as opposed to
+// a server that is merely slow-but-alive.
+var ErrStreamStalled = errors.New("stream stalled")
+
+// defaultStreamIdleTimeout is used when the caller doesn't configure one.
+const defaultStreamIdleTimeout = 15 * time.Second
+
 // Client handles OpenAI API interactions
 type Client struct {
-	baseURL         string
-	httpClient      *http.Client
-	availableModels []Model
-	apiKey          string
+	baseURL           string
+	httpClient        *http.Client
+	availableModels   []Model
+	apiKey            string
+	streamIdleTimeout time.Duration
 }
 
 // Model represents an OpenAI model
@@ -64,6 +75,15 @@ type CompletionResponse struct {
 	RequestTime  time.Time
 	ResponseTime time.Time
 	TTFT         time.Duration // Time to first token (for streaming)
+	// ScheduledAt is the time an open-loop LoadProfile intended this request
+	// to be sent. When set, Metrics.RecordRequest measures latency from here
+	// instead of RequestTime, so queueing delay under overload is counted
+	// (coordinated-omission correction) rather than hidden.
+	ScheduledAt time.Time
+	// MaxInterTokenLatency is the largest gap observed between successive
+	// delta chunks of a streaming response, surfacing tail-of-tail jitter
+	// that TTFT and average latency alone don't show.
+	MaxInterTokenLatency time.Duration
 }
 
 // Choice represents a completion choice
@@ -100,27 +120,32 @@ func isIPAddress(s string) bool {
 	return net.ParseIP(host) != nil
 }
 
-// NewClient creates a new OpenAI API client
-func NewClient(serverURL string, timeout time.Duration, apiKey string) *Client {
+// NewClient creates a new OpenAI API client. streamIdleTimeout bounds how
+// long a streaming completion may go without producing a new chunk before
+// it's considered stalled; <= 0 uses defaultStreamIdleTimeout.
+func NewClient(serverURL string, timeout time.Duration, apiKey string, streamIdleTimeout time.Duration) *Client {
+	if streamIdleTimeout <= 0 {
+		streamIdleTimeout = defaultStreamIdleTimeout
+	}
 	// Ensure URL has proper scheme
 	if !strings.HasPrefix(serverURL, "http://") && !strings.HasPrefix(serverURL, "https://") {
 		// Use HTTP for IP addresses and localhost, HTTPS for domains
-		if strings.Contains(serverURL, "localhost") || 
-		   strings.HasPrefix(serverURL, "127.") ||
-		   isIPAddress(serverURL) {
+		if strings.Contains(serverURL, "localhost") ||
+			strings.HasPrefix(serverURL, "127.") ||
+			isIPAddress(serverURL) {
 			serverURL = "http://" + serverURL
 		} else {
 			serverURL = "https://" + serverURL
 		}
 	}
-	
+
 	// Parse and validate URL
 	u, err := url.Parse(serverURL)
 	if err != nil {
 		// Fallback to basic URL
-		if strings.Contains(serverURL, "localhost") || 
-		   strings.HasPrefix(serverURL, "127.") ||
-		   isIPAddress(serverURL) {
+		if strings.Contains(serverURL, "localhost") ||
+			strings.HasPrefix(serverURL, "127.") ||
+			isIPAddress(serverURL) {
 			serverURL = "http://" + serverURL
 		} else {
 			serverURL = "https://" + serverURL
@@ -128,18 +153,19 @@ func NewClient(serverURL string, timeout time.Duration, apiKey string) *Client {
 	} else {
 		serverURL = u.String()
 	}
-	
+
 	// Remove trailing slash
 	serverURL = strings.TrimSuffix(serverURL, "/")
-	
+
 	// Use environment variable as fallback
 	if apiKey == "" {
 		apiKey = os.Getenv("OPENAI_API_KEY")
 	}
-	
+
 	return &Client{
-		baseURL: serverURL,
-		apiKey:  apiKey,
+		baseURL:           serverURL,
+		apiKey:            apiKey,
+		streamIdleTimeout: streamIdleTimeout,
 		httpClient: &http.Client{
 			Timeout: timeout,
 			Transport: &http.Transport{
@@ -160,65 +186,77 @@ func (c *Client) ListModels(ctx context.Context) ([]Model, error) {
 	} else {
 		url = c.baseURL + "/v1/models"
 	}
-	
+
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
-	
+
 	req.Header.Set("Content-Type", "application/json")
-	
+
 	// Add API key if available
 	if c.apiKey != "" {
 		req.Header.Set("Authorization", "Bearer "+c.apiKey)
 	}
-	
+
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make request: %w", err)
 	}
 	defer resp.Body.Close()
-	
+
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
 	}
-	
+
 	var modelsResp ModelsResponse
 	if err := json.NewDecoder(resp.Body).Decode(&modelsResp); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
-	
+
 	return modelsResp.Data, nil
 }
 
-// CreateCompletion creates a non-streaming completion
-func (c *Client) CreateCompletion(ctx context.Context, model, prompt string) (*CompletionResponse, error) {
+// defaultMaxTokens is used when the caller doesn't request a specific
+// output-token budget.
+const defaultMaxTokens = 150
+
+// CreateCompletion creates a non-streaming completion. maxTokens <= 0 uses
+// defaultMaxTokens.
+func (c *Client) CreateCompletion(ctx context.Context, model, prompt string, maxTokens int) (*CompletionResponse, error) {
+	if maxTokens <= 0 {
+		maxTokens = defaultMaxTokens
+	}
 	reqBody := CompletionRequest{
 		Model: model,
 		Messages: []Message{
 			{Role: "user", Content: prompt},
 		},
-		MaxTokens:   150,
+		MaxTokens:   maxTokens,
 		Temperature: 0.7,
 		Stream:      false,
 	}
-	
+
 	return c.makeCompletionRequest(ctx, reqBody)
 }
 
-// CreateStreamingCompletion creates a streaming completion
-func (c *Client) CreateStreamingCompletion(ctx context.Context, model, prompt string) (*CompletionResponse, error) {
+// CreateStreamingCompletion creates a streaming completion. maxTokens <= 0
+// uses defaultMaxTokens.
+func (c *Client) CreateStreamingCompletion(ctx context.Context, model, prompt string, maxTokens int) (*CompletionResponse, error) {
+	if maxTokens <= 0 {
+		maxTokens = defaultMaxTokens
+	}
 	reqBody := CompletionRequest{
 		Model: model,
 		Messages: []Message{
 			{Role: "user", Content: prompt},
 		},
-		MaxTokens:   150,
+		MaxTokens:   maxTokens,
 		Temperature: 0.7,
 		Stream:      true,
 	}
-	
+
 	return c.makeStreamingRequest(ctx, reqBody)
 }
 
@@ -228,7 +266,7 @@ func (c *Client) makeCompletionRequest(ctx context.Context, reqBody CompletionRe
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
-	
+
 	// Handle URLs that already contain versioned paths
 	var url string
 	if strings.Contains(c.baseURL, "/v1") {
@@ -240,36 +278,36 @@ func (c *Client) makeCompletionRequest(ctx context.Context, reqBody CompletionRe
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
-	
+
 	req.Header.Set("Content-Type", "application/json")
-	
+
 	// Add API key if available
 	if c.apiKey != "" {
 		req.Header.Set("Authorization", "Bearer "+c.apiKey)
 	}
-	
+
 	requestTime := time.Now()
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make request: %w", err)
 	}
 	defer resp.Body.Close()
-	
+
 	responseTime := time.Now()
-	
+
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
 	}
-	
+
 	var completion CompletionResponse
 	if err := json.NewDecoder(resp.Body).Decode(&completion); err != nil {
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
-	
+
 	completion.RequestTime = requestTime
 	completion.ResponseTime = responseTime
-	
+
 	return &completion, nil
 }
 
@@ -279,7 +317,7 @@ func (c *Client) makeStreamingRequest(ctx context.Context, reqBody CompletionReq
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
-	
+
 	// Handle URLs that already contain versioned paths
 	var url string
 	if strings.Contains(c.baseURL, "/v1") {
@@ -291,88 +329,133 @@ func (c *Client) makeStreamingRequest(ctx context.Context, reqBody CompletionReq
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
-	
+
 	req.Header.Set("Content-Type", "application/json")
-	
+
 	// Add API key if available
 	if c.apiKey != "" {
 		req.Header.Set("Authorization", "Bearer "+c.apiKey)
 	}
 	req.Header.Set("Accept", "text/event-stream")
-	
+
 	requestTime := time.Now()
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make request: %w", err)
 	}
 	defer resp.Body.Close()
-	
+
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		return nil, fmt.Errorf("API error %d: %s", resp.StatusCode, string(body))
 	}
-	
+
 	// Process streaming response
 	completion, err := c.processStreamingResponse(resp.Body, requestTime)
 	if err != nil {
 		return nil, err
 	}
-	
+
 	return completion, nil
 }
 
-// processStreamingResponse processes the SSE stream and returns a complete response
-func (c *Client) processStreamingResponse(body io.Reader, requestTime time.Time) (*CompletionResponse, error) {
+// streamOutcome arbitrates between the idle timer and the main goroutine
+// deciding how a stream ended. Whichever side wins the CAS from
+// streamRunning is authoritative; the loser's independent write (an
+// unsynchronized bool/int32 store from each side, as this used to be)
+// could otherwise land after the winner's and flip the final verdict.
+type streamOutcome int32
+
+const (
+	streamRunning streamOutcome = iota
+	streamStalled
+	streamDone
+)
+
+// processStreamingResponse processes the SSE stream and returns a complete
+// response. An inter-token deadline guards against a model that emits the
+// first token and then hangs: the idle timer is reset on every chunk and,
+// if it fires before the main goroutine has already recorded [DONE], closes
+// body so the scanner unblocks with an error we translate into
+// ErrStreamStalled instead of silently inflating TTFT/latency.
+func (c *Client) processStreamingResponse(body io.ReadCloser, requestTime time.Time) (*CompletionResponse, error) {
 	scanner := bufio.NewScanner(body)
-	
+
 	var completion CompletionResponse
 	var content strings.Builder
 	var firstTokenTime time.Time
+	var lastTokenTime time.Time
+	var maxInterTokenLatency time.Duration
 	var lastChunk *StreamResponse
-	
+	tokensReceived := 0
+	var outcome int32 = int32(streamRunning)
+
 	completion.RequestTime = requestTime
-	
+
+	idleTimer := time.AfterFunc(c.streamIdleTimeout, func() {
+		if atomic.CompareAndSwapInt32(&outcome, int32(streamRunning), int32(streamStalled)) {
+			body.Close()
+		}
+	})
+	defer idleTimer.Stop()
+
 	for scanner.Scan() {
 		line := scanner.Text()
-		
+
 		// Skip empty lines and non-data lines
 		if !strings.HasPrefix(line, "data: ") {
 			continue
 		}
-		
+
 		// Extract JSON data
 		data := strings.TrimPrefix(line, "data: ")
-		
-		// Check for end of stream
+
+		// Check for end of stream. The CAS here and in the idle timer's
+		// callback contend on the same outcome: whichever runs first wins,
+		// so a timer fire racing with this line can no longer clobber a
+		// legitimately completed stream (or vice versa) via two
+		// independent unsynchronized stores.
 		if data == "[DONE]" {
+			idleTimer.Stop()
+			atomic.CompareAndSwapInt32(&outcome, int32(streamRunning), int32(streamDone))
 			break
 		}
-		
+
 		var chunk StreamResponse
 		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
 			continue // Skip malformed chunks
 		}
-		
-		// Record first token time
-		if firstTokenTime.IsZero() && len(chunk.Choices) > 0 && chunk.Choices[0].Delta.Content != "" {
-			firstTokenTime = time.Now()
-			completion.TTFT = firstTokenTime.Sub(requestTime)
-		}
-		
-		// Accumulate content
+
+		idleTimer.Reset(c.streamIdleTimeout)
+
 		if len(chunk.Choices) > 0 && chunk.Choices[0].Delta.Content != "" {
+			now := time.Now()
+			if firstTokenTime.IsZero() {
+				firstTokenTime = now
+				completion.TTFT = firstTokenTime.Sub(requestTime)
+			} else if gap := now.Sub(lastTokenTime); gap > maxInterTokenLatency {
+				maxInterTokenLatency = gap
+			}
+			lastTokenTime = now
+			tokensReceived++
+
 			content.WriteString(chunk.Choices[0].Delta.Content)
 		}
-		
+
 		lastChunk = &chunk
 	}
-	
+
 	completion.ResponseTime = time.Now()
-	
+	completion.MaxInterTokenLatency = maxInterTokenLatency
+
+	if streamOutcome(atomic.LoadInt32(&outcome)) == streamStalled {
+		return nil, fmt.Errorf("%w after %d tokens", ErrStreamStalled, tokensReceived)
+	}
+
 	if err := scanner.Err(); err != nil {
 		return nil, fmt.Errorf("error reading stream: %w", err)
 	}
-	
+
 	// Build final completion response
 	if lastChunk != nil {
 		completion.ID = lastChunk.ID
@@ -389,7 +472,7 @@ func (c *Client) processStreamingResponse(body io.Reader, requestTime time.Time)
 				FinishReason: "stop",
 			},
 		}
-		
+
 		// Estimate token usage (rough approximation)
 		promptTokens := len(strings.Fields(completion.Choices[0].Message.Content)) / 4
 		completionTokens := len(strings.Fields(content.String())) / 4
@@ -399,6 +482,6 @@ func (c *Client) processStreamingResponse(body io.Reader, requestTime time.Time)
 			TotalTokens:      promptTokens + completionTokens,
 		}
 	}
-	
+
 	return &completion, nil
-}
\ No newline at end of file
+}
@@ -11,6 +11,10 @@ import (
 	"github.com/pterm/pterm"
 )
 
+// sparklineWidth is the number of characters printModelResult's latency and
+// TTFT shape rows are normalized to.
+const sparklineWidth = 40
+
 // Display handles all output formatting and progress display
 type Display struct {
 	noColor bool
@@ -19,11 +23,11 @@ type Display struct {
 // NewDisplay creates a new display handler
 func NewDisplay(noColor bool) *Display {
 	d := &Display{noColor: noColor}
-	
+
 	if noColor {
 		pterm.DisableColor()
 	}
-	
+
 	return d
 }
 
@@ -52,19 +56,24 @@ func (d *Display) PrintError(message string) {
 	pterm.Error.Println(message)
 }
 
-// ShowProgress displays real-time progress during benchmarking using pterm
-func (d *Display) ShowProgress(ctx context.Context, metrics *Metrics, duration time.Duration) {
-	startTime := time.Now()  
+// ShowProgress displays real-time progress during benchmarking using pterm.
+// concurrency is polled on every tick and shown in the title so a live
+// adaptive concurrency controller's current limit is visible as it
+// converges; pass a func that always returns the same value for a fixed
+// concurrency run.
+func (d *Display) ShowProgress(ctx context.Context, metrics *Metrics, duration time.Duration, concurrency func() int) {
+	startTime := time.Now()
 	liveMetrics := NewLiveMetrics(metrics)
-	
+	defer liveMetrics.Stop()
+
 	// Create pterm progress bar
 	p, _ := pterm.DefaultProgressbar.WithTotal(int(duration.Seconds())).WithTitle("Running benchmark").Start()
-	
+
 	ticker := time.NewTicker(1 * time.Second)
 	defer ticker.Stop()
-	
+
 	progressDone := make(chan struct{})
-	
+
 	go func() {
 		defer close(progressDone)
 		for {
@@ -72,30 +81,32 @@ func (d *Display) ShowProgress(ctx context.Context, metrics *Metrics, duration t
 			case <-ticker.C:
 				elapsed := time.Since(startTime)
 				remaining := duration - elapsed
-				
+
 				if remaining <= 0 {
 					if p.Current < p.Total {
 						p.Add(p.Total - p.Current) // Set to 100%
 					}
 					continue // Keep updating the title even after 100%
 				}
-				
+
 				// Get current stats
 				_, tps, successful, total, _ := liveMetrics.GetLiveStats()
-				
-				// Simple title showing requests and tokens/sec
+				conc := concurrency()
+
+				// Simple title showing requests, tokens/sec, and the current
+				// concurrency limit (moves live under --adaptive-concurrency)
 				var title string
 				if successful > 0 && tps > 0 {
-					title = fmt.Sprintf("Running benchmark... (Reqs: %d | %.2f Tokens/sec)", total, tps)
+					title = fmt.Sprintf("Running benchmark... (Reqs: %d | TPS: %.2f | Conc: %d)", total, tps, conc)
 				} else {
-					title = fmt.Sprintf("Running benchmark... (Reqs: %d)", total)
+					title = fmt.Sprintf("Running benchmark... (Reqs: %d | Conc: %d)", total, conc)
 				}
-				
+
 				p.UpdateTitle(title)
 				if p.Current < p.Total {
 					p.Add(1)
 				}
-				
+
 			case <-ctx.Done():
 				// Set to 100% when context is done
 				if p.Current < p.Total {
@@ -105,16 +116,16 @@ func (d *Display) ShowProgress(ctx context.Context, metrics *Metrics, duration t
 			}
 		}
 	}()
-	
+
 	// Wait for context to be done
 	<-ctx.Done()
-	
+
 	// Wait for the progress goroutine to finish
 	<-progressDone
-	
+
 	// Keep the completed progress bar visible for a moment
 	time.Sleep(1 * time.Second)
-	
+
 	// Now stop the progress bar
 	p.Stop()
 }
@@ -122,7 +133,7 @@ func (d *Display) ShowProgress(ctx context.Context, metrics *Metrics, duration t
 // PrintResults displays the final benchmark results
 func (d *Display) PrintResults(results []BenchmarkResult, format string) {
 	fmt.Print("\n\n")
-	
+
 	switch format {
 	case "json":
 		d.printJSONResults(results)
@@ -135,17 +146,17 @@ func (d *Display) PrintResults(results []BenchmarkResult, format string) {
 func (d *Display) printTableResults(results []BenchmarkResult) {
 	fmt.Println()
 	pterm.DefaultSection.Println("📈 Benchmark Results")
-	
+
 	if len(results) == 0 {
 		pterm.Warning.Println("No benchmark results to display.")
 		return
 	}
-	
+
 	// Create table data
 	tableData := pterm.TableData{
 		{"Model", "Tokens/sec", "Reqs/sec", "Success Rate", "Avg Latency", "P95 Latency"},
 	}
-	
+
 	for _, result := range results {
 		successRate := 100 - result.ErrorRate
 		tableData = append(tableData, []string{
@@ -157,9 +168,9 @@ func (d *Display) printTableResults(results []BenchmarkResult) {
 			d.formatDuration(result.P95Latency),
 		})
 	}
-	
+
 	pterm.DefaultTable.WithHasHeader().WithData(tableData).Render()
-	
+
 	// Show detailed results for each model
 	for _, result := range results {
 		d.printModelResult(result)
@@ -171,43 +182,56 @@ func (d *Display) printTableResults(results []BenchmarkResult) {
 func (d *Display) printModelResult(result BenchmarkResult) {
 	pterm.Printf("\n%s %s\n", pterm.Bold.Sprint("Model:"), pterm.LightBlue(result.Model))
 	fmt.Printf("%s\n", strings.Repeat("─", 50))
-	
+
 	// Performance metrics
 	pterm.Printf("%-20s %s\n", "Duration:", d.formatDuration(result.Duration))
 	pterm.Printf("%-20s %s\n", "Total Requests:", d.formatInt(result.TotalRequests))
-	pterm.Printf("%-20s %s (%s success rate)\n", 
-		"Successful:", 
+	pterm.Printf("%-20s %s (%s success rate)\n",
+		"Successful:",
 		d.formatInt(result.SuccessfulReqs),
 		d.formatPercentage(100-result.ErrorRate))
-	
+
 	if result.FailedReqs > 0 {
-		pterm.Printf("%-20s %s (%s)\n", 
-			"Failed:", 
+		pterm.Printf("%-20s %s (%s)\n",
+			"Failed:",
 			pterm.Red(d.formatInt(result.FailedReqs)),
 			pterm.Red(d.formatPercentage(result.ErrorRate)))
 	}
-	
+
 	fmt.Println()
-	
+
 	// Throughput metrics
 	pterm.Printf("%-20s %s\n", "Requests/sec:", pterm.Green(d.formatNumber(result.RequestsPerSec)))
 	pterm.Printf("%-20s %s\n", "Tokens/sec:", pterm.Green(d.formatNumber(result.TokensPerSec)))
 	pterm.Printf("%-20s %s\n", "Input Tokens/sec:", d.formatNumber(result.InputTokensPerSec))
 	pterm.Printf("%-20s %s\n", "Output Tokens/sec:", d.formatNumber(result.OutputTokensPerSec))
-	
+
 	fmt.Println()
-	
+
 	// Latency metrics
 	pterm.Printf("%-20s %s\n", "Avg Latency:", d.formatDuration(result.AvgLatency))
+	pterm.Printf("%-20s %s\n", "Std Dev:", d.formatDuration(result.StdDevLatency))
 	pterm.Printf("%-20s %s\n", "Min Latency:", d.formatDuration(result.MinLatency))
 	pterm.Printf("%-20s %s\n", "Max Latency:", d.formatDuration(result.MaxLatency))
+	pterm.Printf("%-20s %s\n", "P50 Latency:", d.formatDuration(result.P50Latency))
+	pterm.Printf("%-20s %s\n", "P75 Latency:", d.formatDuration(result.P75Latency))
+	pterm.Printf("%-20s %s\n", "P90 Latency:", d.formatDuration(result.P90Latency))
 	pterm.Printf("%-20s %s\n", "P95 Latency:", d.formatDuration(result.P95Latency))
 	pterm.Printf("%-20s %s\n", "P99 Latency:", d.formatDuration(result.P99Latency))
-	
+	pterm.Printf("%-20s %s\n", "P99.9 Latency:", d.formatDuration(result.P999Latency))
+	pterm.Printf("%-20s %s\n", "P99.99 Latency:", d.formatDuration(result.P9999Latency))
+
 	if result.AvgTTFT > 0 {
 		pterm.Printf("%-20s %s\n", "Avg TTFT:", d.formatDuration(result.AvgTTFT))
 	}
-	
+
+	if len(result.LatencyBuckets) > 0 {
+		pterm.Printf("%-20s %s\n", "Latency shape:", sparklineFromBuckets(result.LatencyBuckets, sparklineWidth))
+	}
+	if len(result.TTFTBuckets) > 0 {
+		pterm.Printf("%-20s %s\n", "TTFT shape:", sparklineFromBuckets(result.TTFTBuckets, sparklineWidth))
+	}
+
 	// Error breakdown
 	if len(result.Errors) > 0 {
 		fmt.Println()
@@ -273,32 +297,32 @@ func (d *Display) PrintSummary(results []BenchmarkResult) {
 	if len(results) == 0 {
 		return
 	}
-	
+
 	pterm.DefaultSection.Println("📋 Summary")
-	
+
 	var totalRPS, totalTPS float64
 	bestModel := ""
 	bestRPS := 0.0
-	
+
 	for _, result := range results {
 		totalRPS += result.RequestsPerSec
 		totalTPS += result.TokensPerSec
-		
+
 		if result.RequestsPerSec > bestRPS {
 			bestRPS = result.RequestsPerSec
 			bestModel = result.Model
 		}
 	}
-	
+
 	pterm.Printf("Models tested: %s\n", pterm.Bold.Sprintf("%d", len(results)))
 	pterm.Printf("Total RPS: %s\n", pterm.Green(d.formatNumber(totalRPS)))
 	pterm.Printf("Total TPS: %s\n", pterm.Green(d.formatNumber(totalTPS)))
-	
+
 	if bestModel != "" {
-		pterm.Printf("Best performing: %s (%s RPS)\n", 
-			pterm.LightBlue(bestModel), 
+		pterm.Printf("Best performing: %s (%s RPS)\n",
+			pterm.LightBlue(bestModel),
 			pterm.Green(d.formatNumber(bestRPS)))
 	}
-	
+
 	fmt.Println()
-}
\ No newline at end of file
+}
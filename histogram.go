@@ -0,0 +1,347 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"time"
+)
+
+// Histogram is a logarithmic bucketed histogram used to accumulate latency
+// and token-count samples without keeping every observation in memory.
+// Values are organized into buckets of the form 2^exp * (1 + mantissa/N),
+// where N is the number of linear subdivisions per octave derived from
+// sigFigs. This gives roughly constant relative error across the whole
+// range instead of the fixed absolute error a linear histogram would have,
+// which matters because LLM latencies span several orders of magnitude
+// (sub-millisecond cache hits to multi-minute generations).
+type Histogram struct {
+	sigFigs        int
+	subBucketCount int
+	minExp         int
+	maxExp         int
+	counts         []uint64
+	count          uint64
+	sum            float64
+	sumSq          float64
+	min            float64
+	max            float64
+}
+
+// HistogramBucket is one non-empty bucket of a Histogram's raw counts,
+// exported for dumping a distribution's shape (e.g. to a file via
+// --latency-histogram-out) rather than just its summary percentiles.
+type HistogramBucket struct {
+	UpperBound float64 `json:"upper_bound"`
+	Count      uint64  `json:"count"`
+}
+
+// NewHistogram creates a histogram covering [minValue, maxValue] (in the
+// caller's unit) with sigFigs significant decimal digits of resolution
+// within each power-of-two bucket. sigFigs=3 gives ~0.1% relative error.
+func NewHistogram(minValue, maxValue float64, sigFigs int) *Histogram {
+	if minValue < 1 {
+		minValue = 1
+	}
+	subBucketCount := int(math.Ceil(math.Pow(2, math.Ceil(math.Log2(math.Pow(10, float64(sigFigs)))))))
+	return &Histogram{
+		sigFigs:        sigFigs,
+		subBucketCount: subBucketCount,
+		minExp:         int(math.Floor(math.Log2(minValue))),
+		maxExp:         int(math.Floor(math.Log2(maxValue))),
+		counts:         make([]uint64, (int(math.Floor(math.Log2(maxValue)))-int(math.Floor(math.Log2(minValue)))+2)*subBucketCount),
+	}
+}
+
+// bucketIndex maps a value to its slot in counts, clamping to the
+// histogram's configured range.
+func (h *Histogram) bucketIndex(v float64) int {
+	if v < 1 {
+		v = 1
+	}
+	exp := int(math.Floor(math.Log2(v)))
+	if exp < h.minExp {
+		exp = h.minExp
+	}
+	if exp > h.maxExp {
+		exp = h.maxExp
+	}
+
+	bucketBase := math.Exp2(float64(exp))
+	mantissa := int((v/bucketBase - 1.0) * float64(h.subBucketCount))
+	if mantissa < 0 {
+		mantissa = 0
+	}
+	if mantissa >= h.subBucketCount {
+		mantissa = h.subBucketCount - 1
+	}
+
+	return (exp-h.minExp)*h.subBucketCount + mantissa
+}
+
+// valueAtIndex returns the lower bound of the bucket at idx.
+func (h *Histogram) valueAtIndex(idx int) float64 {
+	exp := h.minExp + idx/h.subBucketCount
+	mantissa := idx % h.subBucketCount
+	bucketBase := math.Exp2(float64(exp))
+	return bucketBase * (1.0 + float64(mantissa)/float64(h.subBucketCount))
+}
+
+// Record adds a single observation. O(1): a log2 plus a bucket increment.
+func (h *Histogram) Record(v float64) {
+	if v <= 0 {
+		return
+	}
+
+	idx := h.bucketIndex(v)
+	h.counts[idx]++
+	h.count++
+	h.sum += v
+	h.sumSq += v * v
+
+	if h.count == 1 || v < h.min {
+		h.min = v
+	}
+	if v > h.max {
+		h.max = v
+	}
+}
+
+// Count returns the number of recorded observations.
+func (h *Histogram) Count() uint64 {
+	return h.count
+}
+
+// Sum returns the sum of all recorded observations.
+func (h *Histogram) Sum() float64 {
+	return h.sum
+}
+
+// Mean returns the arithmetic mean of all recorded observations.
+func (h *Histogram) Mean() float64 {
+	if h.count == 0 {
+		return 0
+	}
+	return h.sum / float64(h.count)
+}
+
+// StdDev returns the population standard deviation of all recorded
+// observations, computed from the running sum of squares so it doesn't
+// require a second pass over the data.
+func (h *Histogram) StdDev() float64 {
+	if h.count == 0 {
+		return 0
+	}
+	mean := h.Mean()
+	variance := h.sumSq/float64(h.count) - mean*mean
+	if variance < 0 {
+		// Guards against tiny negative values from floating-point
+		// cancellation when the distribution is nearly degenerate.
+		variance = 0
+	}
+	return math.Sqrt(variance)
+}
+
+// Min returns the smallest recorded observation.
+func (h *Histogram) Min() float64 {
+	return h.min
+}
+
+// Max returns the largest recorded observation.
+func (h *Histogram) Max() float64 {
+	return h.max
+}
+
+// Percentile walks the cumulative bucket counts to the rank at p (0-100)
+// and linearly interpolates within the landing bucket.
+func (h *Histogram) Percentile(p float64) float64 {
+	return h.percentileOverCounts(h.counts, h.count, p)
+}
+
+// percentileOverCounts is Percentile's walk, generalized to operate over
+// any per-bucket counts slice (the histogram's own cumulative counts, or a
+// window's worth of diffed counts from PercentileSince) against the given
+// total observation count.
+func (h *Histogram) percentileOverCounts(counts []uint64, total uint64, p float64) float64 {
+	if total == 0 {
+		return 0
+	}
+
+	target := uint64(math.Ceil(float64(total) * p / 100.0))
+	if target == 0 {
+		target = 1
+	}
+
+	var cumulative uint64
+	for idx, c := range counts {
+		if c == 0 {
+			continue
+		}
+		prev := cumulative
+		cumulative += c
+		if cumulative >= target {
+			lower := h.valueAtIndex(idx)
+			upper := h.valueAtIndex(idx + 1)
+			frac := float64(target-prev) / float64(c)
+			return lower + frac*(upper-lower)
+		}
+	}
+
+	return h.max
+}
+
+// HistogramSnapshot is a point-in-time copy of a Histogram's per-bucket
+// counts, taken by Snapshot and consumed by PercentileSince to compute a
+// percentile over only the observations recorded after the snapshot was
+// taken - e.g. the adaptive concurrency controller's windowed p95 - without
+// keeping a second rolling histogram alongside the cumulative one.
+type HistogramSnapshot struct {
+	counts []uint64
+	count  uint64
+}
+
+// Snapshot captures the histogram's current per-bucket counts.
+func (h *Histogram) Snapshot() HistogramSnapshot {
+	counts := make([]uint64, len(h.counts))
+	copy(counts, h.counts)
+	return HistogramSnapshot{counts: counts, count: h.count}
+}
+
+// PercentileSince returns the percentile at p computed only over
+// observations recorded since snapshot was taken, by walking the
+// difference between the current and snapshotted per-bucket counts. Safe
+// to call repeatedly against snapshots from different points in time,
+// since bucket counts are monotonically non-decreasing between Resets.
+func (h *Histogram) PercentileSince(snapshot HistogramSnapshot, p float64) float64 {
+	windowCount := h.count - snapshot.count
+	if windowCount == 0 {
+		return 0
+	}
+
+	diffs := make([]uint64, len(h.counts))
+	for i := range h.counts {
+		diffs[i] = h.counts[i] - snapshot.counts[i]
+	}
+	return h.percentileOverCounts(diffs, windowCount, p)
+}
+
+// BucketCounts returns the histogram's non-empty buckets in ascending
+// order, each reported with its upper bound so the shape of the
+// distribution (not just summary percentiles) can be inspected or dumped.
+func (h *Histogram) BucketCounts() []HistogramBucket {
+	var buckets []HistogramBucket
+	for idx, c := range h.counts {
+		if c == 0 {
+			continue
+		}
+		buckets = append(buckets, HistogramBucket{
+			UpperBound: h.valueAtIndex(idx + 1),
+			Count:      c,
+		})
+	}
+	return buckets
+}
+
+// sparkBlocks are the density levels a Sparkline cell can take, from empty
+// to fully saturated.
+var sparkBlocks = []rune(" ▁▂▃▄▅▆▇█")
+
+// Sparkline renders the histogram's bucket counts as a compact row of width
+// characters, binned evenly across the observed value range and normalized
+// against the densest bin, for eyeballing a distribution's shape (e.g. a
+// long tail) next to its summary percentiles.
+func (h *Histogram) Sparkline(width int) string {
+	return sparklineFromBuckets(h.BucketCounts(), width)
+}
+
+// sparklineFromBuckets is Sparkline's implementation, split out so it can
+// also render from a BenchmarkResult's saved bucket counts without keeping
+// the live Histogram around.
+func sparklineFromBuckets(buckets []HistogramBucket, width int) string {
+	if len(buckets) == 0 || width <= 0 {
+		return ""
+	}
+
+	minBound, maxBound := buckets[0].UpperBound, buckets[len(buckets)-1].UpperBound
+	span := maxBound - minBound
+
+	bins := make([]uint64, width)
+	for _, b := range buckets {
+		idx := 0
+		if span > 0 {
+			idx = int((b.UpperBound - minBound) / span * float64(width-1))
+			if idx >= width {
+				idx = width - 1
+			}
+		}
+		bins[idx] += b.Count
+	}
+
+	var maxBin uint64
+	for _, c := range bins {
+		if c > maxBin {
+			maxBin = c
+		}
+	}
+
+	row := make([]rune, width)
+	for i, c := range bins {
+		level := 0
+		if maxBin > 0 {
+			level = int(math.Round(float64(c) / float64(maxBin) * float64(len(sparkBlocks)-1)))
+		}
+		row[i] = sparkBlocks[level]
+	}
+	return string(row)
+}
+
+// latencyHistogramDump is one line of the --latency-histogram-out JSONL
+// file: a model's raw latency bucket counts, in nanoseconds, for offline
+// analysis (e.g. plotting a full distribution instead of a handful of
+// percentiles).
+type latencyHistogramDump struct {
+	Model   string            `json:"model"`
+	Buckets []HistogramBucket `json:"buckets"`
+}
+
+// dumpLatencyHistogram appends one model's raw latency bucket counts to
+// path as a JSONL line, creating the file if needed.
+func dumpLatencyHistogram(path, model string, hist *Histogram) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open latency histogram output: %w", err)
+	}
+	defer f.Close()
+
+	line, err := json.Marshal(latencyHistogramDump{Model: model, Buckets: hist.BucketCounts()})
+	if err != nil {
+		return fmt.Errorf("failed to marshal latency histogram: %w", err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write latency histogram: %w", err)
+	}
+	return nil
+}
+
+// RecordDuration and PercentileDuration are convenience wrappers for
+// histograms storing time.Duration samples as nanoseconds.
+func (h *Histogram) RecordDuration(d time.Duration) {
+	h.Record(float64(d))
+}
+
+func (h *Histogram) PercentileDuration(p float64) time.Duration {
+	return time.Duration(h.Percentile(p))
+}
+
+func (h *Histogram) MeanDuration() time.Duration {
+	return time.Duration(h.Mean())
+}
+
+func (h *Histogram) MinDuration() time.Duration {
+	return time.Duration(h.Min())
+}
+
+func (h *Histogram) MaxDuration() time.Duration {
+	return time.Duration(h.Max())
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LoadProfile generates a stream of scheduled-send tickets that drive when
+// the next open-loop request should fire. A central scheduler goroutine
+// runs the profile and hands tickets to a pool of workers, so latency under
+// overload is measured from the scheduled time rather than from whenever a
+// worker actually got around to dispatching (coordinated-omission
+// correction). The existing "spawn N workers that loop forever" model is
+// left in place as the closed-loop default and does not use a LoadProfile.
+type LoadProfile interface {
+	// Run emits one scheduled time.Time per ticket on the returned channel
+	// until ctx is cancelled or the profile's own duration elapses, then
+	// closes the channel.
+	Run(ctx context.Context) <-chan time.Time
+}
+
+// PoissonProfile emits tickets at a constant target RPS with exponentially
+// distributed inter-arrival times, matching an open-loop Poisson arrival
+// process.
+type PoissonProfile struct {
+	RPS float64
+}
+
+func (p *PoissonProfile) Run(ctx context.Context) <-chan time.Time {
+	ch := make(chan time.Time)
+	go func() {
+		defer close(ch)
+		next := time.Now()
+		for {
+			next = next.Add(poissonInterval(p.RPS))
+			if !sleepUntil(ctx, next) {
+				return
+			}
+			select {
+			case ch <- next:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// RampProfile linearly increases the target RPS from Start to End over
+// Duration, sampling each inter-arrival interval from the instantaneous
+// rate.
+type RampProfile struct {
+	StartRPS float64
+	EndRPS   float64
+	Duration time.Duration
+}
+
+func (p *RampProfile) Run(ctx context.Context) <-chan time.Time {
+	ch := make(chan time.Time)
+	go func() {
+		defer close(ch)
+		start := time.Now()
+		next := start
+		for {
+			elapsed := time.Since(start)
+			if elapsed >= p.Duration {
+				return
+			}
+			frac := elapsed.Seconds() / p.Duration.Seconds()
+			rps := p.StartRPS + (p.EndRPS-p.StartRPS)*frac
+			next = next.Add(poissonInterval(rps))
+			if !sleepUntil(ctx, next) {
+				return
+			}
+			select {
+			case ch <- next:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// LoadStage is one stage of a StepProfile: hold RPS constant for Duration
+// before advancing to the next stage.
+type LoadStage struct {
+	RPS      float64
+	Duration time.Duration
+}
+
+// StepProfile holds a constant RPS for each stage in turn, then advances,
+// useful for finding the load level where a server starts to degrade.
+type StepProfile struct {
+	Stages []LoadStage
+}
+
+func (p *StepProfile) Run(ctx context.Context) <-chan time.Time {
+	ch := make(chan time.Time)
+	go func() {
+		defer close(ch)
+		for _, stage := range p.Stages {
+			stageStart := time.Now()
+			next := stageStart
+			for {
+				if time.Since(stageStart) >= stage.Duration {
+					break
+				}
+				next = next.Add(poissonInterval(stage.RPS))
+				if !sleepUntil(ctx, next) {
+					return
+				}
+				select {
+				case ch <- next:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return ch
+}
+
+// poissonInterval samples an exponentially distributed inter-arrival
+// duration for a Poisson process with the given mean rate.
+func poissonInterval(rps float64) time.Duration {
+	if rps <= 0 {
+		return time.Second
+	}
+	meanInterval := float64(time.Second) / rps
+	return time.Duration(rand.ExpFloat64() * meanInterval)
+}
+
+// sleepUntil blocks until t or ctx cancellation, returning false in the
+// latter case.
+func sleepUntil(ctx context.Context, t time.Time) bool {
+	d := time.Until(t)
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// ParseLoadProfile parses a --profile flag value into a LoadProfile.
+// Supported forms:
+//
+//	poisson:50rps
+//	ramp:10->200rps
+//	steps:10rps/30s,50rps/30s
+//
+// An empty spec returns (nil, nil), signaling the caller to fall back to
+// the closed-loop concurrency model.
+func ParseLoadProfile(spec string, benchDuration time.Duration) (LoadProfile, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, nil
+	}
+
+	kind, rest, ok := strings.Cut(spec, ":")
+	if !ok {
+		return nil, fmt.Errorf("invalid --profile %q: expected kind:params", spec)
+	}
+
+	switch kind {
+	case "poisson":
+		rps, err := parseRPS(rest)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --profile %q: %w", spec, err)
+		}
+		return &PoissonProfile{RPS: rps}, nil
+
+	case "ramp":
+		bounds := strings.Split(rest, "->")
+		if len(bounds) != 2 {
+			return nil, fmt.Errorf("invalid --profile %q: expected ramp:start->endrps", spec)
+		}
+		startRPS, err := parseRPS(bounds[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid --profile %q: %w", spec, err)
+		}
+		endRPS, err := parseRPS(bounds[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid --profile %q: %w", spec, err)
+		}
+		return &RampProfile{StartRPS: startRPS, EndRPS: endRPS, Duration: benchDuration}, nil
+
+	case "steps":
+		var stages []LoadStage
+		for _, part := range strings.Split(rest, ",") {
+			rpsStr, durStr, ok := strings.Cut(part, "/")
+			if !ok {
+				return nil, fmt.Errorf("invalid --profile %q: expected rps/duration per stage", spec)
+			}
+			rps, err := parseRPS(rpsStr)
+			if err != nil {
+				return nil, fmt.Errorf("invalid --profile %q: %w", spec, err)
+			}
+			dur, err := time.ParseDuration(durStr)
+			if err != nil {
+				return nil, fmt.Errorf("invalid --profile %q: %w", spec, err)
+			}
+			stages = append(stages, LoadStage{RPS: rps, Duration: dur})
+		}
+		if len(stages) == 0 {
+			return nil, fmt.Errorf("invalid --profile %q: no stages", spec)
+		}
+		return &StepProfile{Stages: stages}, nil
+
+	default:
+		return nil, fmt.Errorf("invalid --profile %q: unknown kind %q", spec, kind)
+	}
+}
+
+func parseRPS(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	s = strings.TrimSuffix(s, "rps")
+	return strconv.ParseFloat(s, 64)
+}
@@ -13,19 +13,32 @@ import (
 
 // Config holds all benchmark configuration
 type Config struct {
-	ServerURL    string
-	Duration     time.Duration
-	Concurrency  int
-	Models       []string
-	Timeout      time.Duration
-	Warmup       time.Duration
-	Streaming    bool
-	PromptSize   string
-	Format       string
-	Verbose      bool
-	NoColor      bool
-	APIKey       string
-	SkipDiscovery bool
+	ServerURL           string
+	Duration            time.Duration
+	Concurrency         int
+	Models              []string
+	Timeout             time.Duration
+	Warmup              time.Duration
+	Streaming           bool
+	PromptSize          string
+	Format              string
+	Verbose             bool
+	NoColor             bool
+	APIKey              string
+	SkipDiscovery       bool
+	MetricsAddr         string
+	Profile             string
+	AutoConcurrency     bool
+	SLOP95              time.Duration
+	PromptFile          string
+	InputTokensDist     string
+	OutputTokens        int
+	PushgatewayURL      string
+	PushgatewayJob      string
+	StreamIdleTimeout   time.Duration
+	LatencyHistogramOut string
+	AdaptiveConcurrency bool
+	MaxConcurrency      int
 }
 
 var config Config
@@ -53,15 +66,28 @@ func init() {
 	rootCmd.Flags().BoolVar(&config.NoColor, "no-color", false, "Disable colored output")
 	rootCmd.Flags().StringVarP(&config.APIKey, "api-key", "k", "", "API key (or use OPENAI_API_KEY env var)")
 	rootCmd.Flags().BoolVar(&config.SkipDiscovery, "skip-discovery", false, "Skip model discovery, use specified models directly")
+	rootCmd.Flags().StringVar(&config.MetricsAddr, "metrics-addr", "", "Address to serve Prometheus /metrics on while the benchmark runs (e.g. :9090, disabled by default)")
+	rootCmd.Flags().StringVar(&config.Profile, "profile", "", "Open-loop load shape: poisson:50rps | ramp:10->200rps | steps:10rps/30s,50rps/30s (default: closed-loop concurrency)")
+	rootCmd.Flags().BoolVar(&config.AutoConcurrency, "auto-concurrency", false, "Search for the largest concurrency whose p95 latency stays under --slo-p95 (default: fixed concurrency 1)")
+	rootCmd.Flags().DurationVar(&config.SLOP95, "slo-p95", 2*time.Second, "p95 latency budget used by --auto-concurrency")
+	rootCmd.Flags().StringVar(&config.PromptFile, "prompt-file", "", "JSONL file of ShareGPT-style conversations to draw prompts from (default: built-in --prompt-size corpus)")
+	rootCmd.Flags().StringVar(&config.InputTokensDist, "input-tokens-dist", "", "Target input-token distribution, e.g. lognormal:512,0.5, sized by a ~4-chars-per-token estimate rather than the target model's real tokenizer (default: use prompts as-is)")
+	rootCmd.Flags().IntVar(&config.OutputTokens, "output-tokens", 0, "max_tokens to request per completion (0 = client default)")
+	rootCmd.Flags().StringVar(&config.PushgatewayURL, "pushgateway-url", "", "Prometheus Pushgateway base URL to push each model's final result to (disabled by default)")
+	rootCmd.Flags().StringVar(&config.PushgatewayJob, "pushgateway-job", "aibench", "Pushgateway job name used with --pushgateway-url")
+	rootCmd.Flags().DurationVar(&config.StreamIdleTimeout, "stream-idle-timeout", 15*time.Second, "Max gap between streamed tokens before a streaming request is considered stalled")
+	rootCmd.Flags().StringVar(&config.LatencyHistogramOut, "latency-histogram-out", "", "Append each model's raw latency histogram buckets as a JSONL line to this file (disabled by default)")
+	rootCmd.Flags().BoolVar(&config.AdaptiveConcurrency, "adaptive-concurrency", false, "Continuously raise/lower concurrency during the run based on observed p95 latency and error rate, instead of a fixed or pre-run-searched value")
+	rootCmd.Flags().IntVar(&config.MaxConcurrency, "max-concurrency", 0, "Upper bound on concurrency for --adaptive-concurrency (0 = use the same ceiling as --auto-concurrency)")
 }
 
 func runBenchmark(cmd *cobra.Command, args []string) error {
 	config.ServerURL = args[0]
-	
+
 	// Setup signal handling
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	
+
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 	go func() {
@@ -69,7 +95,7 @@ func runBenchmark(cmd *cobra.Command, args []string) error {
 		fmt.Println("\nReceived interrupt, stopping benchmark...")
 		cancel()
 	}()
-	
+
 	// Create and run benchmarker
 	benchmarker := NewBenchmarker(config)
 	return benchmarker.Run(ctx)
@@ -80,4 +106,4 @@ func main() {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
-}
\ No newline at end of file
+}
@@ -1,23 +1,43 @@
 package main
 
 import (
-	"sort"
+	"math"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// histogram bounds shared by all per-model Metrics instances. Latency and
+// TTFT are recorded in nanoseconds from ~1µs to ~1000s; token counts are
+// recorded as plain counts from 1 to a generous upper bound.
+const (
+	latencyHistMinNanos = 1e3  // 1µs
+	latencyHistMaxNanos = 1e12 // 1000s
+	tokenHistMin        = 1
+	tokenHistMax        = 1 << 20
+	histSigFigs         = 3
+)
+
 // Metrics collects and calculates benchmark statistics
 type Metrics struct {
-	mu                  sync.RWMutex
-	startTime           time.Time
-	requestTimes        []time.Duration
-	ttftTimes           []time.Duration
-	inputTokens         []int
-	outputTokens        []int
-	totalRequests       int64
-	successfulRequests  int64
-	failedRequests      int64
-	errors              map[string]int
+	mu                 sync.RWMutex
+	startTime          time.Time
+	latencyHist        *Histogram
+	ttftHist           *Histogram
+	inputTokenHist     *Histogram
+	outputTokenHist    *Histogram
+	totalRequests      int64
+	successfulRequests int64
+	failedRequests     int64
+	errors             map[string]int
+
+	// totalAtomic, successfulAtomic and tokensAtomic mirror the counters
+	// above but are updated with atomic ops so LiveMetrics's ticker
+	// goroutine can read them without taking mu and contending with the
+	// request-recording hot path.
+	totalAtomic      int64
+	successfulAtomic int64
+	tokensAtomic     int64
 }
 
 // MetricsStats represents calculated statistics
@@ -33,18 +53,28 @@ type MetricsStats struct {
 	AvgLatency         time.Duration
 	MinLatency         time.Duration
 	MaxLatency         time.Duration
+	StdDevLatency      time.Duration
+	P50Latency         time.Duration
+	P75Latency         time.Duration
+	P90Latency         time.Duration
 	P95Latency         time.Duration
 	P99Latency         time.Duration
-	AvgTTFT           time.Duration
-	ErrorRate         float64
-	Errors            map[string]int
+	P999Latency        time.Duration
+	P9999Latency       time.Duration
+	AvgTTFT            time.Duration
+	ErrorRate          float64
+	Errors             map[string]int
 }
 
 // NewMetrics creates a new metrics collector
 func NewMetrics() *Metrics {
 	return &Metrics{
-		startTime: time.Now(),
-		errors:    make(map[string]int),
+		startTime:       time.Now(),
+		errors:          make(map[string]int),
+		latencyHist:     NewHistogram(latencyHistMinNanos, latencyHistMaxNanos, histSigFigs),
+		ttftHist:        NewHistogram(latencyHistMinNanos, latencyHistMaxNanos, histSigFigs),
+		inputTokenHist:  NewHistogram(tokenHistMin, tokenHistMax, histSigFigs),
+		outputTokenHist: NewHistogram(tokenHistMin, tokenHistMax, histSigFigs),
 	}
 }
 
@@ -52,25 +82,30 @@ func NewMetrics() *Metrics {
 func (m *Metrics) Reset() {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	
+
 	m.startTime = time.Now()
-	m.requestTimes = nil
-	m.ttftTimes = nil
-	m.inputTokens = nil
-	m.outputTokens = nil
+	m.latencyHist = NewHistogram(latencyHistMinNanos, latencyHistMaxNanos, histSigFigs)
+	m.ttftHist = NewHistogram(latencyHistMinNanos, latencyHistMaxNanos, histSigFigs)
+	m.inputTokenHist = NewHistogram(tokenHistMin, tokenHistMax, histSigFigs)
+	m.outputTokenHist = NewHistogram(tokenHistMin, tokenHistMax, histSigFigs)
 	m.totalRequests = 0
 	m.successfulRequests = 0
 	m.failedRequests = 0
 	m.errors = make(map[string]int)
+
+	atomic.StoreInt64(&m.totalAtomic, 0)
+	atomic.StoreInt64(&m.successfulAtomic, 0)
+	atomic.StoreInt64(&m.tokensAtomic, 0)
 }
 
 // RecordRequest records the result of a single request
 func (m *Metrics) RecordRequest(resp *CompletionResponse, err error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
-	
+
 	m.totalRequests++
-	
+	atomic.AddInt64(&m.totalAtomic, 1)
+
 	if err != nil {
 		m.failedRequests++
 		errMsg := err.Error()
@@ -81,95 +116,153 @@ func (m *Metrics) RecordRequest(resp *CompletionResponse, err error) {
 		m.errors[errMsg]++
 		return
 	}
-	
+
 	if resp == nil {
 		m.failedRequests++
 		m.errors["nil response"]++
 		return
 	}
-	
+
 	m.successfulRequests++
-	
-	// Record latency
-	latency := resp.ResponseTime.Sub(resp.RequestTime)
-	m.requestTimes = append(m.requestTimes, latency)
-	
+	atomic.AddInt64(&m.successfulAtomic, 1)
+	atomic.AddInt64(&m.tokensAtomic, int64(resp.Usage.PromptTokens+resp.Usage.CompletionTokens))
+
+	// Record latency. When the request was scheduled by an open-loop
+	// LoadProfile, measure from the scheduled-send time instead of the
+	// actual dispatch time so queueing delay under overload is counted.
+	latencyFrom := resp.RequestTime
+	if !resp.ScheduledAt.IsZero() {
+		latencyFrom = resp.ScheduledAt
+	}
+	latency := resp.ResponseTime.Sub(latencyFrom)
+	m.latencyHist.RecordDuration(latency)
+
 	// Record TTFT for streaming requests
 	if resp.TTFT > 0 {
-		m.ttftTimes = append(m.ttftTimes, resp.TTFT)
+		m.ttftHist.RecordDuration(resp.TTFT)
 	}
-	
+
 	// Record token counts
-	m.inputTokens = append(m.inputTokens, resp.Usage.PromptTokens)
-	m.outputTokens = append(m.outputTokens, resp.Usage.CompletionTokens)
+	if resp.Usage.PromptTokens > 0 {
+		m.inputTokenHist.Record(float64(resp.Usage.PromptTokens))
+	}
+	if resp.Usage.CompletionTokens > 0 {
+		m.outputTokenHist.Record(float64(resp.Usage.CompletionTokens))
+	}
 }
 
 // GetStats calculates and returns current statistics
 func (m *Metrics) GetStats() MetricsStats {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
-	
+
 	duration := time.Since(m.startTime)
-	
+
 	stats := MetricsStats{
 		Duration:           duration,
 		TotalRequests:      m.totalRequests,
 		SuccessfulRequests: m.successfulRequests,
 		FailedRequests:     m.failedRequests,
-		Errors:            make(map[string]int),
+		Errors:             make(map[string]int),
 	}
-	
+
 	// Copy errors map
 	for k, v := range m.errors {
 		stats.Errors[k] = v
 	}
-	
+
 	// Calculate error rate
 	if m.totalRequests > 0 {
 		stats.ErrorRate = float64(m.failedRequests) / float64(m.totalRequests) * 100
 	}
-	
+
 	// Calculate RPS
 	if duration.Seconds() > 0 {
 		stats.RequestsPerSec = float64(m.successfulRequests) / duration.Seconds()
 	}
-	
-	// Calculate token rates and latency stats
-	if len(m.requestTimes) > 0 {
-		stats.AvgLatency = m.calculateAverage(m.requestTimes)
-		stats.MinLatency = m.calculateMin(m.requestTimes)
-		stats.MaxLatency = m.calculateMax(m.requestTimes)
-		stats.P95Latency = m.calculatePercentile(m.requestTimes, 95)
-		stats.P99Latency = m.calculatePercentile(m.requestTimes, 99)
+
+	// Calculate latency stats from the histogram
+	if m.latencyHist.Count() > 0 {
+		stats.AvgLatency = m.latencyHist.MeanDuration()
+		stats.MinLatency = m.latencyHist.MinDuration()
+		stats.MaxLatency = m.latencyHist.MaxDuration()
+		stats.StdDevLatency = time.Duration(m.latencyHist.StdDev())
+		stats.P50Latency = m.latencyHist.PercentileDuration(50)
+		stats.P75Latency = m.latencyHist.PercentileDuration(75)
+		stats.P90Latency = m.latencyHist.PercentileDuration(90)
+		stats.P95Latency = m.latencyHist.PercentileDuration(95)
+		stats.P99Latency = m.latencyHist.PercentileDuration(99)
+		stats.P999Latency = m.latencyHist.PercentileDuration(99.9)
+		stats.P9999Latency = m.latencyHist.PercentileDuration(99.99)
 	}
-	
-	if len(m.ttftTimes) > 0 {
-		stats.AvgTTFT = m.calculateAverage(m.ttftTimes)
+
+	if m.ttftHist.Count() > 0 {
+		stats.AvgTTFT = m.ttftHist.MeanDuration()
 	}
-	
+
 	// Calculate token rates
 	if duration.Seconds() > 0 {
-		totalInputTokens := m.sumInts(m.inputTokens)
-		totalOutputTokens := m.sumInts(m.outputTokens)
-		
-		stats.InputTokensPerSec = float64(totalInputTokens) / duration.Seconds()
-		stats.OutputTokensPerSec = float64(totalOutputTokens) / duration.Seconds()
+		stats.InputTokensPerSec = m.inputTokenHist.Sum() / duration.Seconds()
+		stats.OutputTokensPerSec = m.outputTokenHist.Sum() / duration.Seconds()
 		stats.TokensPerSec = stats.InputTokensPerSec + stats.OutputTokensPerSec
 	}
-	
+
 	return stats
 }
 
+// LatencyHistogram returns the underlying latency histogram so callers can
+// inspect its raw bucket counts (e.g. to dump them via
+// --latency-histogram-out) beyond the summary percentiles in MetricsStats.
+func (m *Metrics) LatencyHistogram() *Histogram {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.latencyHist
+}
+
+// TTFTHistogram returns the underlying TTFT histogram so callers can
+// inspect its raw bucket counts beyond the summary percentiles in
+// MetricsStats.
+func (m *Metrics) TTFTHistogram() *Histogram {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.ttftHist
+}
+
+// LatencySnapshot captures the current latency histogram state for later
+// use with LatencyPercentileSince, so a caller (e.g. the adaptive
+// concurrency controller) can compute a p95 over just the window since
+// the snapshot instead of the whole run's cumulative samples.
+func (m *Metrics) LatencySnapshot() HistogramSnapshot {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.latencyHist.Snapshot()
+}
+
+// LatencyPercentileSince returns the latency percentile at p computed only
+// over requests recorded since snapshot was taken.
+func (m *Metrics) LatencyPercentileSince(snapshot HistogramSnapshot, p float64) time.Duration {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return time.Duration(m.latencyHist.PercentileSince(snapshot, p))
+}
+
+// RequestCounters returns the current total and successful request counts
+// via atomic loads, for computing a windowed delta (e.g. a per-interval
+// error rate) without taking mu.
+func (m *Metrics) RequestCounters() (total, successful int64) {
+	return atomic.LoadInt64(&m.totalAtomic), atomic.LoadInt64(&m.successfulAtomic)
+}
+
 // GetCurrentRPS returns the current requests per second
 func (m *Metrics) GetCurrentRPS() float64 {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
-	
+
 	duration := time.Since(m.startTime)
 	if duration.Seconds() <= 0 {
 		return 0
 	}
-	
+
 	return float64(m.successfulRequests) / duration.Seconds()
 }
 
@@ -177,148 +270,172 @@ func (m *Metrics) GetCurrentRPS() float64 {
 func (m *Metrics) GetCurrentTokensPerSec() float64 {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
-	
+
 	duration := time.Since(m.startTime)
 	if duration.Seconds() <= 0 {
 		return 0
 	}
-	
-	totalTokens := m.sumInts(m.inputTokens) + m.sumInts(m.outputTokens)
-	return float64(totalTokens) / duration.Seconds()
+
+	totalTokens := m.inputTokenHist.Sum() + m.outputTokenHist.Sum()
+	return totalTokens / duration.Seconds()
 }
 
-// Helper functions
+// liveMetricsRingSize is the number of one-second buckets kept for the
+// Recent60s window.
+const liveMetricsRingSize = 60
 
-func (m *Metrics) calculateAverage(durations []time.Duration) time.Duration {
-	if len(durations) == 0 {
-		return 0
-	}
-	
-	var total int64
-	for _, d := range durations {
-		total += int64(d)
-	}
-	
-	return time.Duration(total / int64(len(durations)))
+// liveBucket holds the successful-request and token deltas observed in one
+// tick of LiveMetrics's ring buffer.
+type liveBucket struct {
+	reqs   int64
+	tokens int64
 }
 
-func (m *Metrics) calculateMin(durations []time.Duration) time.Duration {
-	if len(durations) == 0 {
-		return 0
+// LiveMetrics tracks recent request/token rates for display during a run.
+// A dedicated ticker goroutine samples Metrics's atomic counters once a
+// second into a ring buffer of per-second deltas, so Recent1s/10s/60s and
+// an EWMA can be read without ever blocking on Metrics.mu, even while the
+// benchmark is hammering it with RecordRequest calls.
+type LiveMetrics struct {
+	metrics *Metrics
+
+	mu       sync.Mutex
+	ring     [liveMetricsRingSize]liveBucket
+	ringPos  int
+	filled   int
+	lastReqs int64
+	lastToks int64
+	lastTick time.Time
+	halfLife time.Duration
+	ewmaRPS  float64
+	ewmaTPS  float64
+
+	stopOnce sync.Once
+	stopCh   chan struct{}
+	doneCh   chan struct{}
+}
+
+// NewLiveMetrics creates a wrapper for live metrics access and starts its
+// background sampling goroutine. Callers must call Stop when done.
+func NewLiveMetrics(metrics *Metrics) *LiveMetrics {
+	lm := &LiveMetrics{
+		metrics:  metrics,
+		lastTick: time.Now(),
+		halfLife: 5 * time.Second,
+		stopCh:   make(chan struct{}),
+		doneCh:   make(chan struct{}),
 	}
-	
-	min := durations[0]
-	for _, d := range durations[1:] {
-		if d < min {
-			min = d
+	go lm.run()
+	return lm
+}
+
+// Stop halts the sampling goroutine. Safe to call multiple times.
+func (lm *LiveMetrics) Stop() {
+	lm.stopOnce.Do(func() {
+		close(lm.stopCh)
+		<-lm.doneCh
+	})
+}
+
+func (lm *LiveMetrics) run() {
+	defer close(lm.doneCh)
+
+	ticker := time.NewTicker(1 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			lm.tick()
+		case <-lm.stopCh:
+			return
 		}
 	}
-	
-	return min
 }
 
-func (m *Metrics) calculateMax(durations []time.Duration) time.Duration {
-	if len(durations) == 0 {
-		return 0
+// tick samples the atomic counters and folds the delta into the ring
+// buffer and EWMA. It never touches Metrics.mu.
+func (lm *LiveMetrics) tick() {
+	reqs := atomic.LoadInt64(&lm.metrics.successfulAtomic)
+	toks := atomic.LoadInt64(&lm.metrics.tokensAtomic)
+	now := time.Now()
+
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+
+	dt := now.Sub(lm.lastTick).Seconds()
+	if dt <= 0 {
+		dt = 1
 	}
-	
-	max := durations[0]
-	for _, d := range durations[1:] {
-		if d > max {
-			max = d
-		}
+	deltaReqs := reqs - lm.lastReqs
+	deltaToks := toks - lm.lastToks
+	lm.lastReqs = reqs
+	lm.lastToks = toks
+	lm.lastTick = now
+
+	lm.ring[lm.ringPos] = liveBucket{reqs: deltaReqs, tokens: deltaToks}
+	lm.ringPos = (lm.ringPos + 1) % liveMetricsRingSize
+	if lm.filled < liveMetricsRingSize {
+		lm.filled++
 	}
-	
-	return max
+
+	alpha := 1 - math.Exp(-dt/lm.halfLife.Seconds())
+	lm.ewmaRPS = alpha*(float64(deltaReqs)/dt) + (1-alpha)*lm.ewmaRPS
+	lm.ewmaTPS = alpha*(float64(deltaToks)/dt) + (1-alpha)*lm.ewmaTPS
 }
 
-func (m *Metrics) calculatePercentile(durations []time.Duration, percentile int) time.Duration {
-	if len(durations) == 0 {
-		return 0
+// recentRates sums the trailing n one-second buckets and returns (rps, tps).
+func (lm *LiveMetrics) recentRates(n int) (float64, float64) {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+
+	if n > lm.filled {
+		n = lm.filled
 	}
-	
-	// Make a copy and sort it
-	sorted := make([]time.Duration, len(durations))
-	copy(sorted, durations)
-	sort.Slice(sorted, func(i, j int) bool {
-		return sorted[i] < sorted[j]
-	})
-	
-	index := int(float64(len(sorted)) * float64(percentile) / 100.0)
-	if index >= len(sorted) {
-		index = len(sorted) - 1
+	if n <= 0 {
+		return 0, 0
 	}
-	
-	return sorted[index]
-}
 
-func (m *Metrics) sumInts(values []int) int {
-	sum := 0
-	for _, v := range values {
-		sum += v
+	var reqs, toks int64
+	for i := 0; i < n; i++ {
+		idx := ((lm.ringPos-1-i)%liveMetricsRingSize + liveMetricsRingSize) % liveMetricsRingSize
+		reqs += lm.ring[idx].reqs
+		toks += lm.ring[idx].tokens
 	}
-	return sum
-}
 
-// LiveMetrics provides thread-safe access to current metrics for display
-type LiveMetrics struct {
-	metrics *Metrics
-	lastReqs int64
-	lastTokens int
-	lastTime time.Time
-	recentRPS float64
-	recentTPS float64
+	return float64(reqs) / float64(n), float64(toks) / float64(n)
 }
 
-// NewLiveMetrics creates a wrapper for live metrics access
-func NewLiveMetrics(metrics *Metrics) *LiveMetrics {
-	return &LiveMetrics{
-		metrics: metrics,
-		lastTime: time.Now(),
-	}
+// Recent1s returns the (rps, tps) observed in the last second.
+func (lm *LiveMetrics) Recent1s() (float64, float64) { return lm.recentRates(1) }
+
+// Recent10s returns the (rps, tps) averaged over the last 10 seconds.
+func (lm *LiveMetrics) Recent10s() (float64, float64) { return lm.recentRates(10) }
+
+// Recent60s returns the (rps, tps) averaged over the last 60 seconds.
+func (lm *LiveMetrics) Recent60s() (float64, float64) { return lm.recentRates(60) }
+
+// EWMA returns the exponentially weighted moving average (rps, tps), with
+// the half-life configured on this LiveMetrics (default 5s).
+func (lm *LiveMetrics) EWMA() (float64, float64) {
+	lm.mu.Lock()
+	defer lm.mu.Unlock()
+	return lm.ewmaRPS, lm.ewmaTPS
 }
 
-// GetLiveStats returns current stats without blocking the metrics collector
+// GetLiveStats returns current stats without blocking the metrics
+// collector. It favors the EWMA, falling back to the 10s trailing average
+// while the EWMA is still warming up, so stalls and warmup ramps show up
+// promptly instead of being smoothed away by a single stale snapshot diff.
 func (lm *LiveMetrics) GetLiveStats() (float64, float64, int64, int64, time.Duration) {
-	lm.metrics.mu.RLock()
-	defer lm.metrics.mu.RUnlock()
-	
-	duration := time.Since(lm.metrics.startTime)
-	rps := 0.0
-	tps := 0.0
-	
-	if duration.Seconds() > 0 {
-		rps = float64(lm.metrics.successfulRequests) / duration.Seconds()
-		totalTokens := lm.metrics.sumInts(lm.metrics.inputTokens) + lm.metrics.sumInts(lm.metrics.outputTokens)
-		tps = float64(totalTokens) / duration.Seconds()
-	}
-	
-	// Calculate recent rates (last 5 seconds)
-	now := time.Now()
-	if now.Sub(lm.lastTime) >= 1*time.Second {
-		currentReqs := lm.metrics.successfulRequests
-		currentTokens := lm.metrics.sumInts(lm.metrics.inputTokens) + lm.metrics.sumInts(lm.metrics.outputTokens)
-		timeDiff := now.Sub(lm.lastTime).Seconds()
-		
-		if timeDiff > 0 {
-			lm.recentRPS = float64(currentReqs-lm.lastReqs) / timeDiff
-			lm.recentTPS = float64(currentTokens-lm.lastTokens) / timeDiff
-		}
-		
-		lm.lastReqs = currentReqs
-		lm.lastTokens = currentTokens
-		lm.lastTime = now
+	rps, tps := lm.Recent10s()
+	if ewmaRPS, ewmaTPS := lm.EWMA(); ewmaRPS > 0 || ewmaTPS > 0 {
+		rps, tps = ewmaRPS, ewmaTPS
 	}
-	
-	// Use recent rates if they're available and make sense
-	displayRPS := rps
-	displayTPS := tps
-	if lm.recentRPS > 0 && duration.Seconds() > 3 {
-		displayRPS = lm.recentRPS
-	}
-	if lm.recentTPS > 0 && duration.Seconds() > 3 {
-		displayTPS = lm.recentTPS
-	}
-	
-	return displayRPS, displayTPS, lm.metrics.successfulRequests, lm.metrics.totalRequests, duration
-}
\ No newline at end of file
+
+	duration := time.Since(lm.metrics.startTime)
+	successful := atomic.LoadInt64(&lm.metrics.successfulAtomic)
+	total := atomic.LoadInt64(&lm.metrics.totalAtomic)
+
+	return rps, tps, successful, total, duration
+}
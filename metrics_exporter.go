@@ -0,0 +1,269 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// latencyBuckets are the cumulative histogram bucket upper bounds (seconds)
+// used for request latency and TTFT. They span typical LLM response times,
+// from fast cache hits to long multi-minute completions.
+var latencyBuckets = []float64{
+	0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 20, 30, 60, 120,
+}
+
+// bucketedHistogram is a minimal cumulative histogram matching the
+// Prometheus exposition format: N bucket upper bounds plus an implicit +Inf
+// bucket, each holding the count of observations <= that bound.
+type bucketedHistogram struct {
+	bounds []float64
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+func newBucketedHistogram(bounds []float64) *bucketedHistogram {
+	return &bucketedHistogram{bounds: bounds, counts: make([]uint64, len(bounds))}
+}
+
+// observe records a single value, expressed in the same unit as bounds.
+func (h *bucketedHistogram) observe(v float64) {
+	h.sum += v
+	h.count++
+	idx := sort.SearchFloat64s(h.bounds, v)
+	for i := idx; i < len(h.counts); i++ {
+		h.counts[i]++
+	}
+}
+
+// MetricsExporter serves a Prometheus text-format /metrics endpoint that
+// mirrors the live Metrics collector while a benchmark is running, so
+// scraping tools can chart long runs alongside the terminal output.
+type MetricsExporter struct {
+	addr   string
+	server *http.Server
+
+	mu                 sync.Mutex
+	model              string
+	inFlight           int64
+	totalRequests      int64
+	successfulRequests int64
+	failedRequests     int64
+	errorsByClass      map[string]int64
+	inputTokens        int64
+	outputTokens       int64
+	startTime          time.Time
+	latencyHist        *bucketedHistogram
+	ttftHist           *bucketedHistogram
+}
+
+// NewMetricsExporter creates an exporter listening on addr. Call Start to
+// begin serving and Stop to shut it down.
+func NewMetricsExporter(addr string) *MetricsExporter {
+	return &MetricsExporter{
+		addr:          addr,
+		errorsByClass: make(map[string]int64),
+		startTime:     time.Now(),
+		latencyHist:   newBucketedHistogram(latencyBuckets),
+		ttftHist:      newBucketedHistogram(latencyBuckets),
+	}
+}
+
+// Start begins serving /metrics in the background. Errors after startup are
+// swallowed since they would otherwise race the benchmark's own output.
+func (e *MetricsExporter) Start() error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", e.handleMetrics)
+
+	e.server = &http.Server{Addr: e.addr, Handler: mux}
+
+	ln, err := net.Listen("tcp", e.addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", e.addr, err)
+	}
+
+	go e.server.Serve(ln)
+	return nil
+}
+
+// Stop gracefully shuts down the listener.
+func (e *MetricsExporter) Stop(ctx context.Context) error {
+	if e.server == nil {
+		return nil
+	}
+	return e.server.Shutdown(ctx)
+}
+
+// SetModel updates the model label attached to exported series. Call this
+// before benchmarking each model.
+func (e *MetricsExporter) SetModel(model string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.model = model
+}
+
+// Reset clears all accumulated counters and histograms and restarts the
+// rate-calculation clock. Call this before each model starts so a scrape
+// during model B's run can't show counters or buckets left over from
+// model A.
+func (e *MetricsExporter) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.inFlight = 0
+	e.totalRequests = 0
+	e.successfulRequests = 0
+	e.failedRequests = 0
+	e.errorsByClass = make(map[string]int64)
+	e.inputTokens = 0
+	e.outputTokens = 0
+	e.startTime = time.Now()
+	e.latencyHist = newBucketedHistogram(latencyBuckets)
+	e.ttftHist = newBucketedHistogram(latencyBuckets)
+}
+
+// IncInFlight marks the start of an in-flight request.
+func (e *MetricsExporter) IncInFlight() {
+	e.mu.Lock()
+	e.inFlight++
+	e.mu.Unlock()
+}
+
+// DecInFlight marks the completion of an in-flight request.
+func (e *MetricsExporter) DecInFlight() {
+	e.mu.Lock()
+	e.inFlight--
+	e.mu.Unlock()
+}
+
+// RecordRequest folds a completed request into the exported counters and
+// histograms. It mirrors Metrics.RecordRequest, including measuring latency
+// from ScheduledAt when set, and accumulates across the whole model run;
+// call Reset between models.
+func (e *MetricsExporter) RecordRequest(resp *CompletionResponse, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.totalRequests++
+
+	if err != nil {
+		e.failedRequests++
+		e.errorsByClass[classifyError(err)]++
+		return
+	}
+
+	if resp == nil {
+		e.failedRequests++
+		e.errorsByClass["nil response"]++
+		return
+	}
+
+	e.successfulRequests++
+
+	latencyFrom := resp.RequestTime
+	if !resp.ScheduledAt.IsZero() {
+		latencyFrom = resp.ScheduledAt
+	}
+	latency := resp.ResponseTime.Sub(latencyFrom)
+	e.latencyHist.observe(latency.Seconds())
+
+	if resp.TTFT > 0 {
+		e.ttftHist.observe(resp.TTFT.Seconds())
+	}
+
+	e.inputTokens += int64(resp.Usage.PromptTokens)
+	e.outputTokens += int64(resp.Usage.CompletionTokens)
+}
+
+// classifyError reduces an error to a short class label suitable for a
+// Prometheus label value (no high-cardinality request details).
+func classifyError(err error) string {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "timeout") || strings.Contains(msg, "deadline exceeded"):
+		return "timeout"
+	case strings.Contains(msg, "connection refused"):
+		return "connection_refused"
+	case strings.Contains(msg, "API error"):
+		return "api_error"
+	default:
+		return "other"
+	}
+}
+
+func (e *MetricsExporter) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+	elapsed := time.Since(e.startTime).Seconds()
+	rps := 0.0
+	inputTPS := 0.0
+	outputTPS := 0.0
+	if elapsed > 0 {
+		rps = float64(e.successfulRequests) / elapsed
+		inputTPS = float64(e.inputTokens) / elapsed
+		outputTPS = float64(e.outputTokens) / elapsed
+	}
+
+	labels := fmt.Sprintf(`{model=%q}`, e.model)
+
+	fmt.Fprintf(w, "# HELP aibench_requests_total Total completion requests attempted.\n")
+	fmt.Fprintf(w, "# TYPE aibench_requests_total counter\n")
+	fmt.Fprintf(w, "aibench_requests_total%s %d\n", labels, e.totalRequests)
+
+	fmt.Fprintf(w, "# HELP aibench_requests_successful_total Completion requests that succeeded.\n")
+	fmt.Fprintf(w, "# TYPE aibench_requests_successful_total counter\n")
+	fmt.Fprintf(w, "aibench_requests_successful_total%s %d\n", labels, e.successfulRequests)
+
+	fmt.Fprintf(w, "# HELP aibench_requests_failed_total Completion requests that failed.\n")
+	fmt.Fprintf(w, "# TYPE aibench_requests_failed_total counter\n")
+	fmt.Fprintf(w, "aibench_requests_failed_total%s %d\n", labels, e.failedRequests)
+
+	fmt.Fprintf(w, "# HELP aibench_errors_total Failed requests by error class.\n")
+	fmt.Fprintf(w, "# TYPE aibench_errors_total counter\n")
+	for class, count := range e.errorsByClass {
+		fmt.Fprintf(w, "aibench_errors_total{model=%q,class=%q} %d\n", e.model, class, count)
+	}
+
+	fmt.Fprintf(w, "# HELP aibench_in_flight_requests Requests currently in flight.\n")
+	fmt.Fprintf(w, "# TYPE aibench_in_flight_requests gauge\n")
+	fmt.Fprintf(w, "aibench_in_flight_requests%s %d\n", labels, e.inFlight)
+
+	fmt.Fprintf(w, "# HELP aibench_requests_per_second Requests per second since the current model's run started.\n")
+	fmt.Fprintf(w, "# TYPE aibench_requests_per_second gauge\n")
+	fmt.Fprintf(w, "aibench_requests_per_second%s %f\n", labels, rps)
+
+	fmt.Fprintf(w, "# HELP aibench_input_tokens_per_second Input tokens per second since the current model's run started.\n")
+	fmt.Fprintf(w, "# TYPE aibench_input_tokens_per_second gauge\n")
+	fmt.Fprintf(w, "aibench_input_tokens_per_second%s %f\n", labels, inputTPS)
+
+	fmt.Fprintf(w, "# HELP aibench_output_tokens_per_second Output tokens per second since the current model's run started.\n")
+	fmt.Fprintf(w, "# TYPE aibench_output_tokens_per_second gauge\n")
+	fmt.Fprintf(w, "aibench_output_tokens_per_second%s %f\n", labels, outputTPS)
+
+	e.writeHistogram(w, "aibench_request_duration_seconds", "Completion request latency in seconds.", e.latencyHist)
+	e.writeHistogram(w, "aibench_ttft_seconds", "Time to first token in seconds, for streaming requests.", e.ttftHist)
+}
+
+func (e *MetricsExporter) writeHistogram(w http.ResponseWriter, name, help string, h *bucketedHistogram) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(w, "# TYPE %s histogram\n", name)
+	for i, bound := range h.bounds {
+		fmt.Fprintf(w, "%s_bucket{model=%q,le=%q} %d\n", name, e.model, formatBound(bound), h.counts[i])
+	}
+	fmt.Fprintf(w, "%s_bucket{model=%q,le=\"+Inf\"} %d\n", name, e.model, h.count)
+	fmt.Fprintf(w, "%s_sum{model=%q} %f\n", name, e.model, h.sum)
+	fmt.Fprintf(w, "%s_count{model=%q} %d\n", name, e.model, h.count)
+}
+
+func formatBound(v float64) string {
+	return fmt.Sprintf("%g", v)
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"strings"
+	"sync"
+)
+
+// PromptSource yields the next prompt to send and, optionally, the max
+// output tokens that should be requested for it (0 means "use the client's
+// default").
+type PromptSource interface {
+	Next() (prompt string, maxOutputTokens int)
+}
+
+// staticPromptSource round-robins through a fixed prompt corpus, preserving
+// the tool's original zero-config behavior.
+type staticPromptSource struct {
+	prompts []string
+	next    int
+}
+
+func newStaticPromptSource(prompts []string) *staticPromptSource {
+	return &staticPromptSource{prompts: prompts}
+}
+
+func (s *staticPromptSource) Next() (string, int) {
+	p := s.prompts[s.next%len(s.prompts)]
+	s.next++
+	return p, 0
+}
+
+// syncPromptSource makes any PromptSource safe to call from multiple
+// worker goroutines.
+type syncPromptSource struct {
+	mu    sync.Mutex
+	inner PromptSource
+}
+
+func newSyncPromptSource(inner PromptSource) *syncPromptSource {
+	return &syncPromptSource{inner: inner}
+}
+
+func (s *syncPromptSource) Next() (string, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.inner.Next()
+}
+
+// fixedOutputPromptSource wraps a base PromptSource to attach a fixed
+// output-token budget to every prompt, for use when --output-tokens is set
+// without input-length targeting.
+type fixedOutputPromptSource struct {
+	base         PromptSource
+	outputTokens int
+}
+
+func newFixedOutputPromptSource(base PromptSource, outputTokens int) *fixedOutputPromptSource {
+	return &fixedOutputPromptSource{base: base, outputTokens: outputTokens}
+}
+
+func (s *fixedOutputPromptSource) Next() (string, int) {
+	prompt, _ := s.base.Next()
+	return prompt, s.outputTokens
+}
+
+// shareGPTRecord matches the common ShareGPT-style JSONL export format.
+type shareGPTRecord struct {
+	Conversations []struct {
+		From  string `json:"from"`
+		Value string `json:"value"`
+	} `json:"conversations"`
+}
+
+// loadJSONLPrompts reads a ShareGPT-style JSONL file and extracts the first
+// human turn of each conversation as a prompt.
+func loadJSONLPrompts(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open prompt file: %w", err)
+	}
+	defer f.Close()
+
+	var prompts []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var rec shareGPTRecord
+		if err := json.Unmarshal([]byte(line), &rec); err != nil {
+			continue
+		}
+
+		for _, turn := range rec.Conversations {
+			if turn.From == "human" || turn.From == "user" {
+				if strings.TrimSpace(turn.Value) != "" {
+					prompts = append(prompts, turn.Value)
+				}
+				break
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read prompt file: %w", err)
+	}
+	if len(prompts) == 0 {
+		return nil, fmt.Errorf("no usable prompts found in %s", path)
+	}
+
+	return prompts, nil
+}
+
+// lengthSampler draws target input-token counts from a distribution.
+type lengthSampler interface {
+	Sample() int
+}
+
+// lognormalSampler draws target lengths from a log-normal distribution
+// parameterized by the desired mean and the underlying normal's sigma,
+// matching how published LLM-serving benchmarks describe input-length
+// distributions.
+type lognormalSampler struct {
+	mu    float64
+	sigma float64
+}
+
+func newLognormalSampler(targetMean, sigma float64) *lognormalSampler {
+	// Solve for the underlying normal's mu so E[X] = exp(mu + sigma^2/2)
+	// equals targetMean.
+	mu := math.Log(targetMean) - sigma*sigma/2
+	return &lognormalSampler{mu: mu, sigma: sigma}
+}
+
+func (s *lognormalSampler) Sample() int {
+	v := math.Exp(s.mu + s.sigma*rand.NormFloat64())
+	n := int(math.Round(v))
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
+// targetLengthPromptSource wraps a base PromptSource and resizes each
+// prompt, by concatenating further base prompts and truncating, to hit a
+// per-request token-count target sampled from dist. This lets a run be
+// reproduced against a fixed input-token budget instead of whatever
+// arbitrary English strings happen to tokenize to.
+type targetLengthPromptSource struct {
+	base         PromptSource
+	tokenizer    Tokenizer
+	dist         lengthSampler
+	outputTokens int
+}
+
+func newTargetLengthPromptSource(base PromptSource, tokenizer Tokenizer, dist lengthSampler, outputTokens int) *targetLengthPromptSource {
+	return &targetLengthPromptSource{base: base, tokenizer: tokenizer, dist: dist, outputTokens: outputTokens}
+}
+
+func (s *targetLengthPromptSource) Next() (string, int) {
+	target := s.dist.Sample()
+
+	var builder strings.Builder
+	for s.tokenizer.CountTokens(builder.String()) < target {
+		p, _ := s.base.Next()
+		if builder.Len() > 0 {
+			builder.WriteString(" ")
+		}
+		builder.WriteString(p)
+	}
+
+	prompt := s.tokenizer.Truncate(builder.String(), target)
+	return prompt, s.outputTokens
+}
+
+// parseInputTokensDist parses a --input-tokens-dist flag value, currently
+// supporting "lognormal:mean,sigma".
+func parseInputTokensDist(spec string) (lengthSampler, error) {
+	kind, rest, ok := strings.Cut(spec, ":")
+	if !ok {
+		return nil, fmt.Errorf("invalid --input-tokens-dist %q: expected kind:params", spec)
+	}
+
+	switch kind {
+	case "lognormal":
+		parts := strings.Split(rest, ",")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid --input-tokens-dist %q: expected lognormal:mean,sigma", spec)
+		}
+		var mean, sigma float64
+		if _, err := fmt.Sscanf(parts[0], "%g", &mean); err != nil {
+			return nil, fmt.Errorf("invalid --input-tokens-dist %q: %w", spec, err)
+		}
+		if _, err := fmt.Sscanf(parts[1], "%g", &sigma); err != nil {
+			return nil, fmt.Errorf("invalid --input-tokens-dist %q: %w", spec, err)
+		}
+		return newLognormalSampler(mean, sigma), nil
+	default:
+		return nil, fmt.Errorf("invalid --input-tokens-dist %q: unknown kind %q", spec, kind)
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// PushBenchmarkResult pushes a one-shot summary of a finished model's
+// result to a Prometheus Pushgateway using the standard
+// PUT /metrics/job/<job>/model/<model> protocol, so scheduled or
+// long-running benchmarks can be correlated across model revisions in
+// Grafana instead of only comparing the table/JSON output by hand.
+func PushBenchmarkResult(gatewayURL, job string, result BenchmarkResult) error {
+	var body strings.Builder
+
+	writeGauge := func(name string, value float64) {
+		fmt.Fprintf(&body, "# TYPE %s gauge\n%s %f\n", name, name, value)
+	}
+	writeCounter := func(name string, value int64) {
+		fmt.Fprintf(&body, "# TYPE %s counter\n%s %d\n", name, name, value)
+	}
+
+	writeCounter("aibench_run_requests_total", result.TotalRequests)
+	writeCounter("aibench_run_requests_successful_total", result.SuccessfulReqs)
+	writeCounter("aibench_run_requests_failed_total", result.FailedReqs)
+	writeGauge("aibench_run_requests_per_second", result.RequestsPerSec)
+	writeGauge("aibench_run_tokens_per_second", result.TokensPerSec)
+	writeGauge("aibench_run_p95_latency_seconds", result.P95Latency.Seconds())
+	writeGauge("aibench_run_p99_latency_seconds", result.P99Latency.Seconds())
+	writeGauge("aibench_run_error_rate", result.ErrorRate)
+
+	// PathEscape model and job: model IDs routinely contain "/" (e.g.
+	// "meta-llama/Llama-3-8b-instruct" from HF-style servers), which would
+	// otherwise split into extra path segments and break Pushgateway's
+	// grouping-key parser.
+	pushURL := fmt.Sprintf("%s/metrics/job/%s/model/%s",
+		strings.TrimSuffix(gatewayURL, "/"), url.PathEscape(job), url.PathEscape(result.Model))
+
+	req, err := http.NewRequest(http.MethodPut, pushURL, bytes.NewBufferString(body.String()))
+	if err != nil {
+		return fmt.Errorf("failed to create pushgateway request: %w", err)
+	}
+	req.Header.Set("Content-Type", "text/plain; version=0.0.4")
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to push to pushgateway: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("pushgateway returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package main
+
+import "unicode/utf8"
+
+// Tokenizer estimates token counts for a piece of text so prompts can be
+// sized against a requested input-token budget instead of compared as
+// arbitrary English strings. The only implementation shipped,
+// approxTokenizer, is a char-count heuristic rather than a real BPE
+// tokenizer - see its doc comment for why, and for what that means for
+// --input-tokens-dist's accuracy.
+type Tokenizer interface {
+	// CountTokens returns the estimated number of tokens text encodes to.
+	CountTokens(text string) int
+	// Truncate returns the prefix of text that encodes to at most n tokens.
+	Truncate(text string, n int) string
+}
+
+// approxTokenizer estimates tokens at ~4 characters per token, the common
+// rule of thumb for English text under BPE tokenizers such as cl100k_base.
+// This is intentionally NOT a real tokenizer: a correct implementation
+// needs the target model's merge tables (cl100k_base alone is a multi-MB
+// rank file) fetched or embedded from a source this tool has no network
+// access to assume, and a real BPE/regex pre-tokenization pass to match.
+// --input-tokens-dist therefore targets an approximate length, not an
+// exact token count, and the ratio drifts further from 4:1 on code,
+// non-English text, or punctuation-heavy prompts. Swap in a real BPE
+// Tokenizer here once that data can be sourced for this project.
+type approxTokenizer struct{}
+
+const approxCharsPerToken = 4
+
+func (approxTokenizer) CountTokens(text string) int {
+	if len(text) == 0 {
+		return 0
+	}
+	n := len(text) / approxCharsPerToken
+	if n == 0 {
+		n = 1
+	}
+	return n
+}
+
+func (approxTokenizer) Truncate(text string, n int) string {
+	maxChars := n * approxCharsPerToken
+	if maxChars >= len(text) {
+		return text
+	}
+	// maxChars is a byte offset that can land inside a multi-byte rune;
+	// back off to the start of that rune so the result stays valid UTF-8.
+	for maxChars > 0 && !utf8.RuneStart(text[maxChars]) {
+		maxChars--
+	}
+	return text[:maxChars]
+}